@@ -0,0 +1,61 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import "time"
+
+// Defaults of the GPUAddonSpec, applied by the defaulting webhook and by the
+// controllers to the fields left unset. They must match the
+// +kubebuilder:default markers of the GPUAddonSpec.
+const (
+	GPUAddonDefaultConsolePluginReplicas = int32(2)
+	GPUAddonDefaultUninstallPolicy       = GPUAddonUninstallPolicyWaitForDrain
+	GPUAddonDefaultUninstallTimeout      = time.Hour
+	GPUAddonDefaultFinalizerTimeout      = 30 * time.Minute
+)
+
+// Defaults of the MonitoringSpec, applied by the defaulting webhook and by
+// the controllers to the fields left unset. They must match the
+// +kubebuilder:default markers of the MonitoringSpec.
+const (
+	MonitoringDefaultMode = MonitoringModeDedicated
+
+	MonitoringDefaultPrometheusReplicas       = int32(1)
+	MonitoringDefaultPrometheusRetentionTime  = "24h"
+	MonitoringDefaultPrometheusScrapeInterval = "30s"
+
+	MonitoringDefaultAlertmanagerReplicas      = int32(3)
+	MonitoringDefaultAlertmanagerRetentionTime = "120h"
+
+	MonitoringDefaultThermalThrottlingPercent = int32(10)
+	MonitoringDefaultPowerViolationPercent    = int32(10)
+	MonitoringDefaultThrottlingFor            = "15m"
+	MonitoringDefaultEccDoubleBitErrors       = int32(0)
+
+	MonitoringDefaultPagerDutyGroupBy        = "alertname"
+	MonitoringDefaultPagerDutyGroupWait      = "30s"
+	MonitoringDefaultPagerDutyGroupInterval  = "5m"
+	MonitoringDefaultPagerDutyRepeatInterval = "12h"
+
+	MonitoringDefaultShowbackReportWindow   = "30d"
+	MonitoringDefaultShowbackReportInterval = "24h"
+	MonitoringDefaultShowbackReportFormat   = MonitoringShowbackReportFormatCSV
+
+	MonitoringDefaultIdleGPUThreshold = int32(5)
+	MonitoringDefaultIdleGPUWindow    = "24h"
+	MonitoringDefaultIdleGPUInterval  = "1h"
+)
@@ -0,0 +1,118 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// getCRDSpecSchema returns the schema of the spec of a generated CRD.
+func getCRDSpecSchema(file string) apiextensionsv1.JSONSchemaProps {
+	raw, err := os.ReadFile(filepath.Join("..", "..", "config", "crd", "bases", file))
+	Expect(err).ToNot(HaveOccurred())
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	Expect(yaml.Unmarshal(raw, crd)).To(Succeed())
+	Expect(crd.Spec.Versions).To(HaveLen(1))
+
+	return crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"]
+}
+
+// expectSchemaDefaults checks that the defaulted object holds the default of
+// every field of the schema, within the objects it holds.
+func expectSchemaDefaults(path string, schema apiextensionsv1.JSONSchemaProps, defaulted map[string]interface{}, skipped map[string]bool) {
+	for name, property := range schema.Properties {
+		fieldPath := path + "." + name
+		if skipped[fieldPath] {
+			continue
+		}
+
+		value, ok := defaulted[name]
+		if object, isObject := value.(map[string]interface{}); isObject {
+			expectSchemaDefaults(fieldPath, property, object, skipped)
+			continue
+		}
+
+		if property.Default == nil {
+			continue
+		}
+
+		expected := interface{}(nil)
+		Expect(json.Unmarshal(property.Default.Raw, &expected)).To(Succeed())
+
+		if !ok {
+			// The zero values and empty objects are omitted.
+			Expect(expected).To(Or(Equal(false), Equal(float64(0)), Equal(map[string]interface{}{})),
+				"%s is not defaulted to %v", fieldPath, expected)
+			continue
+		}
+
+		// The durations are rendered differently, e.g. 1h and 1h0m0s.
+		if s, isString := expected.(string); isString {
+			if d, err := time.ParseDuration(s); err == nil {
+				actual, err := time.ParseDuration(value.(string))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(actual).To(Equal(d), "%s", fieldPath)
+				continue
+			}
+		}
+
+		Expect(value).To(Equal(expected), "%s", fieldPath)
+	}
+}
+
+func toJSONObject(v interface{}) map[string]interface{} {
+	raw, err := json.Marshal(v)
+	Expect(err).ToNot(HaveOccurred())
+
+	object := map[string]interface{}{}
+	Expect(json.Unmarshal(raw, &object)).To(Succeed())
+
+	return object
+}
+
+var _ = Describe("Defaults", func() {
+	It("should match the CRD defaults of the GPUAddonSpec", func() {
+		gpuAddon := &GPUAddon{}
+		gpuAddon.Default()
+
+		expectSchemaDefaults("spec", getCRDSpecSchema("nvidia.addons.rh-ecosystem-edge.io_gpuaddons.yaml"),
+			toJSONObject(gpuAddon.Spec), map[string]bool{
+				// A bool cannot tell unset from false, this default is left
+				// to the CRD.
+				"spec.console_plugin_enabled": true,
+			})
+	})
+
+	It("should match the CRD defaults of the MonitoringSpec", func() {
+		m := &Monitoring{}
+		m.Spec.Showback.Report = &MonitoringShowbackReportSpec{}
+		m.Default()
+
+		expectSchemaDefaults("spec", getCRDSpecSchema("nvidia.addons.rh-ecosystem-edge.io_monitorings.yaml"),
+			toJSONObject(m.Spec), nil)
+	})
+})
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -29,18 +30,110 @@ type GPUAddonSpec struct {
 	//+kubebuilder:default:=true
 	// If enabled, addon will deploy the GPU console plugin.
 	ConsolePluginEnabled bool `json:"console_plugin_enabled,omitempty"`
+	//+kubebuilder:default:=2
+	//+kubebuilder:validation:Minimum=1
+	// Number of replicas of the GPU console plugin.
+	ConsolePluginReplicas *int32 `json:"console_plugin_replicas,omitempty"`
+	// Compute resources of the GPU console plugin. Defaults are used if not set.
+	ConsolePluginResources *corev1.ResourceRequirements `json:"console_plugin_resources,omitempty"`
+	// Proxy the GPU console plugin requests to the addon Prometheus so that
+	// the GPU dashboards can query its metrics on behalf of the console user.
+	// In the UserWorkload monitoring mode, the requests go to the tenancy port
+	// of the OpenShift Thanos Querier, the addon namespace as namespace parameter.
+	ConsolePluginPrometheusProxyEnabled bool `json:"console_plugin_prometheus_proxy_enabled,omitempty"`
 	// Optional NVAIE pullsecret
 	NVAIEPullSecret string `json:"nvaie_pullsecret,omitempty"`
+	//+kubebuilder:default:="WaitForDrain"
+	// How the removal of the addon handles the pods still using GPUs.
+	// Immediate removes the addon right away, WaitForDrain waits for the pods
+	// to complete up to the uninstall timeout, and Block waits for them
+	// indefinitely. The force-uninstall annotation overrides the policy.
+	UninstallPolicy GPUAddonUninstallPolicy `json:"uninstall_policy,omitempty"`
+	//+kubebuilder:default:="1h"
+	// How long the WaitForDrain policy waits for the pods using GPUs, from
+	// the removal request, before removing the addon anyway.
+	UninstallTimeout *metav1.Duration `json:"uninstall_timeout,omitempty"`
+	//+kubebuilder:default:="30m"
+	// How long the removal of the addon waits, from the deletion of the
+	// GPUAddon CR, for the resources it manages to be deleted before removing
+	// the finalizers holding them back.
+	FinalizerTimeout *metav1.Duration `json:"finalizer_timeout,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=Immediate;WaitForDrain;Block
+type GPUAddonUninstallPolicy string
+
+const (
+	GPUAddonUninstallPolicyImmediate    GPUAddonUninstallPolicy = "Immediate"
+	GPUAddonUninstallPolicyWaitForDrain GPUAddonUninstallPolicy = "WaitForDrain"
+	GPUAddonUninstallPolicyBlock        GPUAddonUninstallPolicy = "Block"
+)
+
+const (
+	// GPUAddonForceUninstallAnnotation removes the addon regardless of the
+	// uninstall policy when set to "true" on the GPUAddon CR.
+	GPUAddonForceUninstallAnnotation = "nvidia.addons.rh-ecosystem-edge.io/force-uninstall"
+
+	// GPUAddonUninstallBlockedCondition is True while the removal of the
+	// addon waits for the pods using GPUs.
+	GPUAddonUninstallBlockedCondition = "UninstallBlocked"
+)
+
 // GPUAddonStatus defines the observed state of GPUAddon
 type GPUAddonStatus struct {
 	// The state of the addon operator
 	Phase GPUAddonPhase `json:"phase"`
 	// Conditions represent the latest available observations of an object's state
 	Conditions []metav1.Condition `json:"conditions"`
+	// Pods using GPUs which hold back the removal of the addon, truncated to
+	// the first 50.
+	UninstallBlockedBy []GPUAddonWorkload `json:"uninstall_blocked_by,omitempty"`
+	// Progress of the removal of the addon, once the GPUAddon CR is deleted.
+	Uninstall *GPUAddonUninstallStatus `json:"uninstall,omitempty"`
+}
+
+// GPUAddonWorkload is a pod using GPUs.
+type GPUAddonWorkload struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Number of GPUs requested by the pod.
+	GPUs int64 `json:"gpus"`
+}
+
+// GPUAddonUninstallStatus is the progress of the removal of the addon.
+type GPUAddonUninstallStatus struct {
+	// When the GPUAddon CR was deleted.
+	StartTime metav1.Time `json:"start_time"`
+	// When the finalizers of the resources still being deleted are removed.
+	FinalizerDeadline metav1.Time `json:"finalizer_deadline"`
+	// Deletion state of the resources managed by the addon.
+	Resources []GPUAddonResourceStatus `json:"resources"`
+}
+
+// GPUAddonResourceStatus is the deletion state of a resource managed by the
+// addon.
+type GPUAddonResourceStatus struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Pending until the resource is marked for deletion, Deleting while
+	// finalizers hold it back, then Deleted, or FinalizersRemoved when its
+	// finalizers had to be removed past the finalizer deadline.
+	State GPUAddonResourceState `json:"state"`
+	// Finalizers holding back the deletion of the resource.
+	Finalizers []string `json:"finalizers,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=Pending;Deleting;Deleted;FinalizersRemoved
+type GPUAddonResourceState string
+
+const (
+	GPUAddonResourceStatePending           GPUAddonResourceState = "Pending"
+	GPUAddonResourceStateDeleting          GPUAddonResourceState = "Deleting"
+	GPUAddonResourceStateDeleted           GPUAddonResourceState = "Deleted"
+	GPUAddonResourceStateFinalizersRemoved GPUAddonResourceState = "FinalizersRemoved"
+)
+
 // +kubebuilder:validation:Enum=Failed;Idle;Installing;Ready;Updating;Uninstalling
 type GPUAddonPhase string
 
@@ -0,0 +1,163 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+var gpuAddonGroupKind = GroupVersion.WithKind("GPUAddon").GroupKind()
+
+func (r *GPUAddon) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-nvidia-addons-rh-ecosystem-edge-io-v1alpha1-gpuaddon,mutating=true,failurePolicy=fail,sideEffects=None,groups=nvidia.addons.rh-ecosystem-edge.io,resources=gpuaddons,verbs=create;update,versions=v1alpha1,name=mgpuaddon.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &GPUAddon{}
+
+// Default sets the defaults of the fields left unset, as the CRD does for
+// the fields it knows about, e.g. when set to null.
+func (r *GPUAddon) Default() {
+	if r.Spec.ConsolePluginReplicas == nil {
+		r.Spec.ConsolePluginReplicas = int32Ptr(GPUAddonDefaultConsolePluginReplicas)
+	}
+	if r.Spec.UninstallPolicy == "" {
+		r.Spec.UninstallPolicy = GPUAddonDefaultUninstallPolicy
+	}
+	if r.Spec.UninstallTimeout == nil {
+		r.Spec.UninstallTimeout = &metav1.Duration{Duration: GPUAddonDefaultUninstallTimeout}
+	}
+	if r.Spec.FinalizerTimeout == nil {
+		r.Spec.FinalizerTimeout = &metav1.Duration{Duration: GPUAddonDefaultFinalizerTimeout}
+	}
+}
+
+//+kubebuilder:webhook:path=/validate-nvidia-addons-rh-ecosystem-edge-io-v1alpha1-gpuaddon,mutating=false,failurePolicy=fail,sideEffects=None,groups=nvidia.addons.rh-ecosystem-edge.io,resources=gpuaddons,verbs=create;update,versions=v1alpha1,name=vgpuaddon.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &GPUAddon{}
+
+// ValidateCreate only allows the GPUAddon CR named after the addon, which
+// the operator reconciles.
+func (r *GPUAddon) ValidateCreate() error {
+	errs := validateSingletonName(&r.ObjectMeta)
+	errs = append(errs, r.validateSpec()...)
+
+	return toInvalidError(gpuAddonGroupKind, r.Name, errs)
+}
+
+// ValidateUpdate does not hold back the removal of the finalizers of a
+// GPUAddon CR being deleted.
+func (r *GPUAddon) ValidateUpdate(old runtime.Object) error {
+	if r.DeletionTimestamp != nil {
+		return nil
+	}
+
+	return toInvalidError(gpuAddonGroupKind, r.Name, r.validateSpec())
+}
+
+func (r *GPUAddon) ValidateDelete() error {
+	return nil
+}
+
+func (r *GPUAddon) validateSpec() field.ErrorList {
+	errs := field.ErrorList{}
+	spec := field.NewPath("spec")
+
+	if r.Spec.NVAIEPullSecret != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(r.Spec.NVAIEPullSecret) {
+			errs = append(errs, field.Invalid(spec.Child("nvaie_pullsecret"), r.Spec.NVAIEPullSecret, msg))
+		}
+	}
+
+	if r.Spec.ConsolePluginPrometheusProxyEnabled && !r.Spec.ConsolePluginEnabled {
+		errs = append(errs, field.Invalid(spec.Child("console_plugin_prometheus_proxy_enabled"), true,
+			"the Prometheus proxy requires the console plugin to be enabled"))
+	}
+
+	if r.Spec.ConsolePluginReplicas != nil && *r.Spec.ConsolePluginReplicas < 1 {
+		errs = append(errs, field.Invalid(spec.Child("console_plugin_replicas"), *r.Spec.ConsolePluginReplicas,
+			"must be at least 1"))
+	}
+
+	errs = append(errs, validateResources(spec.Child("console_plugin_resources"), r.Spec.ConsolePluginResources)...)
+	errs = append(errs, validatePositiveDuration(spec.Child("uninstall_timeout"), r.Spec.UninstallTimeout)...)
+	errs = append(errs, validatePositiveDuration(spec.Child("finalizer_timeout"), r.Spec.FinalizerTimeout)...)
+
+	return errs
+}
+
+// validateSingletonName checks that a CR is named after the addon, the
+// operator reconciling a single instance of each of its CRDs.
+func validateSingletonName(meta *metav1.ObjectMeta) field.ErrorList {
+	if meta.Name == common.GlobalConfig.AddonID {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(field.NewPath("metadata", "name"), meta.Name,
+			fmt.Sprintf("must be %s, a single instance being supported", common.GlobalConfig.AddonID)),
+	}
+}
+
+// validateResources checks that the requests do not exceed the limits.
+func validateResources(path *field.Path, resources *corev1.ResourceRequirements) field.ErrorList {
+	errs := field.ErrorList{}
+	if resources == nil {
+		return errs
+	}
+
+	for name, request := range resources.Requests {
+		limit, ok := resources.Limits[name]
+		if ok && request.Cmp(limit) > 0 {
+			errs = append(errs, field.Invalid(path.Child("requests").Key(string(name)), request.String(),
+				fmt.Sprintf("must be less than or equal to the %s limit %s", name, limit.String())))
+		}
+	}
+
+	return errs
+}
+
+func validatePositiveDuration(path *field.Path, d *metav1.Duration) field.ErrorList {
+	if d == nil || d.Duration > 0 {
+		return nil
+	}
+
+	return field.ErrorList{field.Invalid(path, d.Duration.String(), "must be positive")}
+}
+
+func toInvalidError(gk schema.GroupKind, name string, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return k8serrors.NewInvalid(gk, name, errs)
+}
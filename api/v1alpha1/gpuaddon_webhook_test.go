@@ -0,0 +1,184 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+func newTestGPUAddon(name string) *GPUAddon {
+	return &GPUAddon{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: common.GlobalConfig.AddonNamespace,
+		},
+		Spec: GPUAddonSpec{
+			ConsolePluginEnabled: true,
+		},
+	}
+}
+
+var _ = Describe("GPUAddon webhook", func() {
+	Context("Defaulting", func() {
+		It("should set the defaults of the fields left unset", func() {
+			gpuAddon := newTestGPUAddon(common.GlobalConfig.AddonID)
+			gpuAddon.Default()
+
+			Expect(*gpuAddon.Spec.ConsolePluginReplicas).To(Equal(int32(2)))
+			Expect(gpuAddon.Spec.UninstallPolicy).To(Equal(GPUAddonUninstallPolicyWaitForDrain))
+			Expect(gpuAddon.Spec.UninstallTimeout.Duration).To(Equal(time.Hour))
+			Expect(gpuAddon.Spec.FinalizerTimeout.Duration).To(Equal(30 * time.Minute))
+		})
+
+		It("should keep the fields set", func() {
+			gpuAddon := newTestGPUAddon(common.GlobalConfig.AddonID)
+			replicas := int32(3)
+			gpuAddon.Spec.ConsolePluginReplicas = &replicas
+			gpuAddon.Spec.UninstallPolicy = GPUAddonUninstallPolicyBlock
+			gpuAddon.Default()
+
+			Expect(*gpuAddon.Spec.ConsolePluginReplicas).To(Equal(int32(3)))
+			Expect(gpuAddon.Spec.UninstallPolicy).To(Equal(GPUAddonUninstallPolicyBlock))
+		})
+	})
+
+	Context("Validation", func() {
+		It("should accept the GPUAddon CR named after the addon", func() {
+			gpuAddon := newTestGPUAddon(common.GlobalConfig.AddonID)
+			gpuAddon.Default()
+
+			Expect(gpuAddon.ValidateCreate()).To(Succeed())
+		})
+
+		It("should reject another GPUAddon CR", func() {
+			err := newTestGPUAddon("other").ValidateCreate()
+
+			Expect(k8serrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("metadata.name")))
+		})
+
+		It("should reject an invalid NVAIE pull secret name", func() {
+			gpuAddon := newTestGPUAddon(common.GlobalConfig.AddonID)
+			gpuAddon.Spec.NVAIEPullSecret = "Not_A_Secret"
+
+			Expect(gpuAddon.ValidateUpdate(gpuAddon)).To(MatchError(ContainSubstring("spec.nvaie_pullsecret")))
+		})
+
+		It("should reject the Prometheus proxy without the console plugin", func() {
+			gpuAddon := newTestGPUAddon(common.GlobalConfig.AddonID)
+			gpuAddon.Spec.ConsolePluginEnabled = false
+			gpuAddon.Spec.ConsolePluginPrometheusProxyEnabled = true
+
+			Expect(gpuAddon.ValidateUpdate(gpuAddon)).To(MatchError(ContainSubstring("spec.console_plugin_prometheus_proxy_enabled")))
+		})
+
+		It("should reject requests exceeding the limits", func() {
+			gpuAddon := newTestGPUAddon(common.GlobalConfig.AddonID)
+			gpuAddon.Spec.ConsolePluginResources = &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			}
+
+			Expect(gpuAddon.ValidateUpdate(gpuAddon)).To(MatchError(ContainSubstring("spec.console_plugin_resources.requests[memory]")))
+		})
+
+		It("should reject a zero timeout", func() {
+			gpuAddon := newTestGPUAddon(common.GlobalConfig.AddonID)
+			gpuAddon.Spec.FinalizerTimeout = &metav1.Duration{}
+
+			Expect(gpuAddon.ValidateUpdate(gpuAddon)).To(MatchError(ContainSubstring("spec.finalizer_timeout")))
+		})
+
+		It("should not hold back the removal of the finalizers", func() {
+			gpuAddon := newTestGPUAddon(common.GlobalConfig.AddonID)
+			gpuAddon.Spec.NVAIEPullSecret = "Not_A_Secret"
+			gpuAddon.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+			Expect(gpuAddon.ValidateUpdate(gpuAddon)).To(Succeed())
+		})
+	})
+
+	DescribeTable("validateSpec",
+		func(mutate func(*GPUAddonSpec), fields ...string) {
+			gpuAddon := newTestGPUAddon(common.GlobalConfig.AddonID)
+			gpuAddon.Default()
+			mutate(&gpuAddon.Spec)
+
+			errs := gpuAddon.validateSpec()
+			invalid := []string{}
+			for _, err := range errs {
+				invalid = append(invalid, err.Field)
+			}
+			Expect(invalid).To(ConsistOf(fields))
+		},
+		Entry("defaulted", func(*GPUAddonSpec) {}),
+		Entry("valid NVAIE pull secret", func(s *GPUAddonSpec) { s.NVAIEPullSecret = "ngc-secret" }),
+		Entry("invalid NVAIE pull secret", func(s *GPUAddonSpec) { s.NVAIEPullSecret = "ngc/secret" },
+			"spec.nvaie_pullsecret"),
+		Entry("no replicas", func(s *GPUAddonSpec) { s.ConsolePluginReplicas = int32Ptr(0) },
+			"spec.console_plugin_replicas"),
+		Entry("requests within the limits", func(s *GPUAddonSpec) {
+			s.ConsolePluginResources = &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			}
+		}),
+		Entry("negative uninstall timeout", func(s *GPUAddonSpec) {
+			s.UninstallTimeout = &metav1.Duration{Duration: -time.Minute}
+		}, "spec.uninstall_timeout"),
+	)
+
+	Context("Admission", func() {
+		BeforeEach(requireEnvTest)
+
+		AfterEach(func() {
+			gpuAddon := newTestGPUAddon(common.GlobalConfig.AddonID)
+			Expect(k8sClient.Delete(context.TODO(), gpuAddon)).To(Or(Succeed(), WithTransform(k8serrors.IsNotFound, BeTrue())))
+		})
+
+		It("should default and validate the GPUAddon CR", func() {
+			Expect(k8sClient.Create(context.TODO(), newTestGPUAddon("other"))).
+				To(WithTransform(k8serrors.IsInvalid, BeTrue()))
+
+			gpuAddon := newTestGPUAddon(common.GlobalConfig.AddonID)
+			Expect(k8sClient.Create(context.TODO(), gpuAddon)).To(Succeed())
+
+			created := &GPUAddon{}
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{
+				Name:      gpuAddon.Name,
+				Namespace: gpuAddon.Namespace,
+			}, created)).To(Succeed())
+			Expect(created.Spec.FinalizerTimeout.Duration).To(Equal(30 * time.Minute))
+
+			created.Spec.NVAIEPullSecret = "Not_A_Secret"
+			Expect(k8sClient.Update(context.TODO(), created)).
+				To(MatchError(ContainSubstring("spec.nvaie_pullsecret")))
+		})
+	})
+})
@@ -17,17 +17,385 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // MonitoringSpec defines the desired monitoring configuration of the NVIDIA GPU Add-on.
 type MonitoringSpec struct {
+	//+kubebuilder:default:="Dedicated"
+	// Prometheus stack handling the addon metrics and alerts. Dedicated deploys
+	// a private Prometheus and Alertmanager in the addon namespace, while
+	// UserWorkload relies on the OpenShift user-workload monitoring, which must
+	// be enabled and must not exclude the addon namespace.
+	Mode MonitoringMode `json:"mode,omitempty"`
+	//+kubebuilder:default:={}
+	// Configuration of the addon Prometheus. Ignored in UserWorkload mode.
+	Prometheus MonitoringPrometheusSpec `json:"prometheus,omitempty"`
+	//+kubebuilder:default:={}
+	// Configuration of the addon Alertmanager. Ignored in UserWorkload mode.
+	Alertmanager MonitoringAlertmanagerSpec `json:"alertmanager,omitempty"`
+	//+kubebuilder:default:={}
+	// Thresholds of the GPU health alerts.
+	GPUHealth MonitoringGPUHealthSpec `json:"gpu_health,omitempty"`
+	//+kubebuilder:default:={}
+	// Grouping of the alerts paged through PagerDuty.
+	PagerDuty MonitoringPagerDutySpec `json:"pagerduty,omitempty"`
+	// Additional receivers of the addon alerts, next to PagerDuty and Dead Man's Snitch.
+	Receivers []MonitoringReceiver `json:"receivers,omitempty"`
+	// Routes of the addon alerts to the additional receivers, evaluated in order.
+	Routes []MonitoringRoute `json:"routes,omitempty"`
+	// Remote write of the GPU metrics to a central endpoint. Disabled if not set.
+	// Ignored in UserWorkload mode, where the remote write is part of the
+	// user-workload monitoring configuration.
+	RemoteWrite *MonitoringRemoteWriteSpec `json:"remote_write,omitempty"`
+	//+kubebuilder:default:={}
+	// Aggregation of the GPU usage per namespace for showback.
+	Showback MonitoringShowbackSpec `json:"showback,omitempty"`
+	//+kubebuilder:default:={}
+	// Detection of the pods holding GPUs they barely use.
+	IdleGPU MonitoringIdleGPUSpec `json:"idle_gpu,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Dedicated;UserWorkload
+type MonitoringMode string
+
+const (
+	MonitoringModeDedicated    MonitoringMode = "Dedicated"
+	MonitoringModeUserWorkload MonitoringMode = "UserWorkload"
+)
+
+// MonitoringPrometheusSpec defines the sizing of the addon Prometheus.
+type MonitoringPrometheusSpec struct {
+	//+kubebuilder:default:=1
+	//+kubebuilder:validation:Minimum=1
+	// Number of replicas of Prometheus.
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Compute resources of Prometheus. Defaults are used if not set.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	//+kubebuilder:default:="24h"
+	//+kubebuilder:validation:Pattern:="^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$"
+	// How long to retain samples, e.g. 24h or 15d.
+	RetentionTime string `json:"retention_time,omitempty"`
+	//+kubebuilder:validation:Pattern:="^(0|([0-9]*[.])?[0-9]+((K|M|G|T|E|P)i?)?B)$"
+	// Maximum amount of disk space used by samples, e.g. 10GB. Unlimited if not set.
+	RetentionSize string `json:"retention_size,omitempty"`
+	//+kubebuilder:default:="30s"
+	//+kubebuilder:validation:Pattern:="^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$"
+	// Interval between scrapes of the GPU metrics endpoints.
+	ScrapeInterval string `json:"scrape_interval,omitempty"`
+	// Persistent storage of Prometheus. An emptyDir is used if not set.
+	VolumeClaimTemplate *MonitoringVolumeClaimTemplate `json:"volume_claim_template,omitempty"`
+	//+kubebuilder:default:={}
+	// Configuration of the kube-rbac-proxy sidecar exposing Prometheus.
+	KubeRBACProxy MonitoringKubeRBACProxySpec `json:"kube_rbac_proxy,omitempty"`
+}
+
+// MonitoringKubeRBACProxySpec defines the logging and sizing of the
+// kube-rbac-proxy sidecar of the addon Prometheus.
+type MonitoringKubeRBACProxySpec struct {
+	//+kubebuilder:default:=0
+	//+kubebuilder:validation:Minimum=0
+	//+kubebuilder:validation:Maximum=10
+	// Log verbosity of kube-rbac-proxy, 10 logging every request.
+	LogLevel int32 `json:"log_level,omitempty"`
+	// Compute resources of kube-rbac-proxy. Defaults are used if not set.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// MonitoringAlertmanagerSpec defines the sizing of the addon Alertmanager.
+type MonitoringAlertmanagerSpec struct {
+	//+kubebuilder:default:=3
+	//+kubebuilder:validation:Minimum=1
+	// Number of replicas of Alertmanager.
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Compute resources of Alertmanager. Defaults are used if not set.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	//+kubebuilder:default:="120h"
+	//+kubebuilder:validation:Pattern:="^[0-9]+(ms|s|m|h)$"
+	// How long to retain data, e.g. silences and notification logs.
+	RetentionTime string `json:"retention_time,omitempty"`
+	// Persistent storage of Alertmanager. An emptyDir is used if not set.
+	VolumeClaimTemplate *MonitoringVolumeClaimTemplate `json:"volume_claim_template,omitempty"`
+}
+
+// MonitoringGPUHealthSpec defines the thresholds of the DCGM based GPU health alerts.
+type MonitoringGPUHealthSpec struct {
+	//+kubebuilder:default:=10
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:validation:Maximum=100
+	// Percentage of time a GPU is thermally throttled above which an alert fires.
+	ThermalThrottlingPercent int32 `json:"thermal_throttling_percent,omitempty"`
+	//+kubebuilder:default:=10
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:validation:Maximum=100
+	// Percentage of time a GPU is power capped above which an alert fires.
+	PowerViolationPercent int32 `json:"power_violation_percent,omitempty"`
+	//+kubebuilder:default:="15m"
+	//+kubebuilder:validation:Pattern:="^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$"
+	// How long a GPU must be throttled before the throttling alerts fire.
+	ThrottlingFor string `json:"throttling_for,omitempty"`
+	//+kubebuilder:default:=0
+	//+kubebuilder:validation:Minimum=0
+	// Number of ECC double-bit errors within 15 minutes above which an alert fires.
+	EccDoubleBitErrors *int32 `json:"ecc_double_bit_errors,omitempty"`
+}
+
+// MonitoringPagerDutySpec defines how the alerts paged through PagerDuty are
+// grouped into notifications.
+type MonitoringPagerDutySpec struct {
+	//+kubebuilder:default:={"alertname"}
+	// Labels by which the alerts are grouped into a single notification.
+	GroupBy []string `json:"group_by,omitempty"`
+	//+kubebuilder:default:="30s"
+	//+kubebuilder:validation:Pattern:="^(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?$"
+	// How long to wait for other alerts of a new group before notifying.
+	GroupWait string `json:"group_wait,omitempty"`
+	//+kubebuilder:default:="5m"
+	//+kubebuilder:validation:Pattern:="^(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?$"
+	// How long to wait before notifying about new alerts of an already notified group.
+	GroupInterval string `json:"group_interval,omitempty"`
+	//+kubebuilder:default:="12h"
+	//+kubebuilder:validation:Pattern:="^(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?$"
+	// How long to wait before notifying again about the same firing alerts.
+	RepeatInterval string `json:"repeat_interval,omitempty"`
+}
+
+// MonitoringReceiver defines an additional receiver of the addon alerts. The
+// referenced Secrets must be in the namespace of the Monitoring CR.
+type MonitoringReceiver struct {
+	//+kubebuilder:validation:Pattern:="^[a-zA-Z0-9][a-zA-Z0-9_-]*$"
+	// Name of the receiver, referenced by the routes.
+	Name string `json:"name"`
+	// Slack notifications.
+	Slack *MonitoringSlackConfig `json:"slack,omitempty"`
+	// Email notifications.
+	Email *MonitoringEmailConfig `json:"email,omitempty"`
+	// Generic webhook notifications.
+	Webhook *MonitoringWebhookConfig `json:"webhook,omitempty"`
+	// OpsGenie notifications.
+	OpsGenie *MonitoringOpsGenieConfig `json:"opsgenie,omitempty"`
+}
+
+// Names of the built-in receivers of the addon alerts, reserved.
+const (
+	MonitoringNullReceiverName           = "null"
+	MonitoringPagerDutyReceiverName      = "pagerduty"
+	MonitoringDeadMansSnitchReceiverName = "DeadMansSnitch"
+)
+
+// The addon Prometheus is only served through the kube-rbac-proxy sidecar
+// behind this Service, e.g. to the console plugin.
+const (
+	MonitoringPrometheusServiceName = "gpuaddon-prometheus-service"
+	MonitoringPrometheusServicePort = 9339
+)
+
+// In UserWorkload mode the addon metrics are queried through the tenancy port
+// of the Thanos Querier of the OpenShift monitoring, which requires the
+// namespace of the queried metrics as namespace parameter.
+const (
+	MonitoringThanosQuerierServiceName      = "thanos-querier"
+	MonitoringThanosQuerierServiceNamespace = "openshift-monitoring"
+	MonitoringThanosQuerierTenancyPort      = 9092
+)
+
+// MonitoringSlackConfig defines the Slack notifications of a receiver.
+type MonitoringSlackConfig struct {
+	// Secret key holding the Slack incoming webhook URL.
+	APIURL corev1.SecretKeySelector `json:"api_url"`
+	// Channel or user to send the notifications to. The webhook default is used if not set.
+	Channel string `json:"channel,omitempty"`
+}
+
+// MonitoringEmailConfig defines the email notifications of a receiver.
+type MonitoringEmailConfig struct {
+	// Email address to send the notifications to.
+	To string `json:"to"`
+	// Sender address.
+	From string `json:"from"`
+	//+kubebuilder:validation:Pattern:="^[^:]+:[0-9]+$"
+	// SMTP host through which the emails are sent, e.g. smtp.example.com:587.
+	Smarthost string `json:"smarthost"`
+	// Username to authenticate to the SMTP host.
+	AuthUsername string `json:"auth_username,omitempty"`
+	// Secret key holding the password to authenticate to the SMTP host.
+	AuthPassword *corev1.SecretKeySelector `json:"auth_password,omitempty"`
+}
+
+// MonitoringWebhookConfig defines the generic webhook notifications of a receiver.
+type MonitoringWebhookConfig struct {
+	// Secret key holding the URL to send the notifications to.
+	URL corev1.SecretKeySelector `json:"url"`
+}
+
+// MonitoringOpsGenieConfig defines the OpsGenie notifications of a receiver.
+type MonitoringOpsGenieConfig struct {
+	// Secret key holding the OpsGenie API key.
+	APIKey corev1.SecretKeySelector `json:"api_key"`
+	// OpsGenie API URL. The Alertmanager default is used if not set.
+	APIURL string `json:"api_url,omitempty"`
+}
+
+// MonitoringRoute routes the addon alerts matching all its criteria to a receiver.
+type MonitoringRoute struct {
+	// Name of the receiver the alerts are sent to.
+	Receiver string `json:"receiver"`
+	// Severities of the alerts to route, e.g. critical or warning. All if empty.
+	Severities []string `json:"severities,omitempty"`
+	// Names of the alerts to route. All if empty.
+	AlertNames []string `json:"alert_names,omitempty"`
+	// Whether the following routes are still evaluated for the alerts matching this route.
+	Continue bool `json:"continue,omitempty"`
+}
+
+// MonitoringRemoteWriteSpec defines the endpoint the addon Prometheus writes
+// the GPU metrics to. The referenced Secrets must be in the namespace of the
+// Monitoring CR.
+type MonitoringRemoteWriteSpec struct {
+	//+kubebuilder:validation:Pattern:="^https?://.+"
+	// URL of the remote write endpoint.
+	URL string `json:"url"`
+	// Basic authentication to the endpoint.
+	BasicAuth *MonitoringBasicAuth `json:"basic_auth,omitempty"`
+	// Secret key holding a bearer token to authenticate to the endpoint.
+	BearerToken *corev1.SecretKeySelector `json:"bearer_token,omitempty"`
+	// TLS configuration of the connection to the endpoint.
+	TLS *MonitoringTLSConfig `json:"tls,omitempty"`
+	// Regular expressions matching the names of the metrics written to the
+	// endpoint. The DCGM and GPU operator metrics are written if empty.
+	MetricsAllowlist []string `json:"metrics_allowlist,omitempty"`
+}
+
+// MonitoringBasicAuth defines the basic authentication to an endpoint.
+type MonitoringBasicAuth struct {
+	// Secret key holding the username.
+	Username corev1.SecretKeySelector `json:"username"`
+	// Secret key holding the password.
+	Password corev1.SecretKeySelector `json:"password"`
+}
+
+// MonitoringTLSConfig defines the TLS configuration of the connection to an endpoint.
+type MonitoringTLSConfig struct {
+	// Secret key holding the PEM encoded CA certificate verifying the endpoint.
+	CA *corev1.SecretKeySelector `json:"ca,omitempty"`
+	// Secret key holding the PEM encoded client certificate.
+	Cert *corev1.SecretKeySelector `json:"cert,omitempty"`
+	// Secret key holding the PEM encoded client key.
+	Key *corev1.SecretKeySelector `json:"key,omitempty"`
+	// Server name used to verify the endpoint certificate.
+	ServerName string `json:"server_name,omitempty"`
+	// Disables the verification of the endpoint certificate.
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty"`
+}
+
+// MonitoringVolumeClaimTemplate describes the PersistentVolumeClaim created
+// for each replica of a monitoring component.
+type MonitoringVolumeClaimTemplate struct {
+	// Name of the StorageClass. The cluster default StorageClass is used if not set.
+	StorageClassName *string `json:"storage_class_name,omitempty"`
+	//+kubebuilder:validation:Required
+	// Requested size of the volume, e.g. 10Gi.
+	Size resource.Quantity `json:"size"`
+}
+
+// MonitoringShowbackSpec defines how the allocated and used GPU time is
+// aggregated per namespace. The allocation is taken from the pod GPU requests
+// reported by kube-state-metrics, which is only scraped in Dedicated mode.
+type MonitoringShowbackSpec struct {
+	//+kubebuilder:validation:Pattern:="^[A-Za-z0-9]([A-Za-z0-9./_-]*[A-Za-z0-9])?$"
+	// Namespace label the GPU time is additionally aggregated by, e.g. a cost center.
+	NamespaceLabel string `json:"namespace_label,omitempty"`
+	// Periodic report of the GPU-hours per namespace, written to the
+	// gpuaddon-showback-report ConfigMap. Disabled if not set.
+	Report *MonitoringShowbackReportSpec `json:"report,omitempty"`
+}
+
+// MonitoringShowbackReportSpec defines the periodic showback report.
+type MonitoringShowbackReportSpec struct {
+	//+kubebuilder:default:="30d"
+	//+kubebuilder:validation:Pattern:="^(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?$"
+	// Window the GPU-hours are summed over, up to the report generation time.
+	// Only the samples retained by the addon Prometheus are summed, see
+	// prometheus.retention_time.
+	Window string `json:"window,omitempty"`
+	//+kubebuilder:default:="24h"
+	//+kubebuilder:validation:Pattern:="^(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?$"
+	// How often the report is generated.
+	Interval string `json:"interval,omitempty"`
+	//+kubebuilder:default:="CSV"
+	// Format of the report.
+	Format MonitoringShowbackReportFormat `json:"format,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=CSV;JSON
+type MonitoringShowbackReportFormat string
+
+const (
+	MonitoringShowbackReportFormatCSV  MonitoringShowbackReportFormat = "CSV"
+	MonitoringShowbackReportFormatJSON MonitoringShowbackReportFormat = "JSON"
+)
+
+// MonitoringIdleGPUSpec defines when the GPUs allocated to a pod are
+// considered idle. A pod is reported once all its GPUs stayed below the
+// utilization threshold over the whole window.
+type MonitoringIdleGPUSpec struct {
+	//+kubebuilder:default:=false
+	// Whether the idle GPUs are looked for.
+	Enabled bool `json:"enabled,omitempty"`
+	//+kubebuilder:default:=5
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:validation:Maximum=100
+	// GPU utilization, in percent, under which a GPU is idle.
+	Threshold int32 `json:"threshold,omitempty"`
+	//+kubebuilder:default:="24h"
+	//+kubebuilder:validation:Pattern:="^(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?$"
+	// Window over which the GPUs must stay below the threshold.
+	Window string `json:"window,omitempty"`
+	//+kubebuilder:default:="1h"
+	//+kubebuilder:validation:Pattern:="^(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?$"
+	// How often the idle GPUs are looked for.
+	Interval string `json:"interval,omitempty"`
+	//+kubebuilder:default:=false
+	// Whether a Warning Event is recorded on the pods holding idle GPUs.
+	PodEvents bool `json:"pod_events,omitempty"`
 }
 
 // MonitoringStatus defines the observed state of Monitoring
 type MonitoringStatus struct {
 	// Conditions represent the latest available observations of an object's state
 	Conditions []metav1.Condition `json:"conditions"`
+	// Pods holding idle GPUs found by the last analysis. Not set unless the
+	// idle GPU detection is enabled.
+	IdleGPUs *MonitoringIdleGPUStatus `json:"idle_gpus,omitempty"`
+}
+
+// MonitoringIdleGPUStatus reports the outcome of the last idle GPU analysis.
+type MonitoringIdleGPUStatus struct {
+	// Time of the last analysis.
+	LastAnalysisTime metav1.Time `json:"last_analysis_time"`
+	// Utilization threshold of the last analysis, in percent.
+	Threshold int32 `json:"threshold"`
+	// Window of the last analysis.
+	Window string `json:"window"`
+	// Number of pods holding idle GPUs.
+	IdlePods int32 `json:"idle_pods"`
+	// Number of idle GPUs.
+	IdleGPUs int32 `json:"idle_gpus"`
+	// Pods holding idle GPUs, sorted by namespace and name and truncated to
+	// the first 100.
+	Pods []MonitoringIdleGPUPod `json:"pods,omitempty"`
+}
+
+// MonitoringIdleGPUPod is a pod holding idle GPUs.
+type MonitoringIdleGPUPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Number of GPUs allocated to the pod.
+	GPUs int32 `json:"gpus"`
+	// Highest utilization of the pod GPUs over the window, in percent.
+	MaxUtilization string `json:"max_utilization"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,279 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+var monitoringGroupKind = GroupVersion.WithKind("Monitoring").GroupKind()
+
+func (r *Monitoring) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-nvidia-addons-rh-ecosystem-edge-io-v1alpha1-monitoring,mutating=true,failurePolicy=fail,sideEffects=None,groups=nvidia.addons.rh-ecosystem-edge.io,resources=monitorings,verbs=create;update,versions=v1alpha1,name=mmonitoring.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &Monitoring{}
+
+// Default sets the defaults of the fields left unset, as the CRD does for
+// the fields it knows about, e.g. when set to null.
+func (r *Monitoring) Default() {
+	spec := &r.Spec
+
+	if spec.Mode == "" {
+		spec.Mode = MonitoringDefaultMode
+	}
+
+	if spec.Prometheus.Replicas == nil {
+		spec.Prometheus.Replicas = int32Ptr(MonitoringDefaultPrometheusReplicas)
+	}
+	if spec.Prometheus.RetentionTime == "" {
+		spec.Prometheus.RetentionTime = MonitoringDefaultPrometheusRetentionTime
+	}
+	if spec.Prometheus.ScrapeInterval == "" {
+		spec.Prometheus.ScrapeInterval = MonitoringDefaultPrometheusScrapeInterval
+	}
+
+	if spec.Alertmanager.Replicas == nil {
+		spec.Alertmanager.Replicas = int32Ptr(MonitoringDefaultAlertmanagerReplicas)
+	}
+	if spec.Alertmanager.RetentionTime == "" {
+		spec.Alertmanager.RetentionTime = MonitoringDefaultAlertmanagerRetentionTime
+	}
+
+	if spec.GPUHealth.ThermalThrottlingPercent == 0 {
+		spec.GPUHealth.ThermalThrottlingPercent = MonitoringDefaultThermalThrottlingPercent
+	}
+	if spec.GPUHealth.PowerViolationPercent == 0 {
+		spec.GPUHealth.PowerViolationPercent = MonitoringDefaultPowerViolationPercent
+	}
+	if spec.GPUHealth.ThrottlingFor == "" {
+		spec.GPUHealth.ThrottlingFor = MonitoringDefaultThrottlingFor
+	}
+	if spec.GPUHealth.EccDoubleBitErrors == nil {
+		spec.GPUHealth.EccDoubleBitErrors = int32Ptr(MonitoringDefaultEccDoubleBitErrors)
+	}
+
+	if len(spec.PagerDuty.GroupBy) == 0 {
+		spec.PagerDuty.GroupBy = []string{MonitoringDefaultPagerDutyGroupBy}
+	}
+	if spec.PagerDuty.GroupWait == "" {
+		spec.PagerDuty.GroupWait = MonitoringDefaultPagerDutyGroupWait
+	}
+	if spec.PagerDuty.GroupInterval == "" {
+		spec.PagerDuty.GroupInterval = MonitoringDefaultPagerDutyGroupInterval
+	}
+	if spec.PagerDuty.RepeatInterval == "" {
+		spec.PagerDuty.RepeatInterval = MonitoringDefaultPagerDutyRepeatInterval
+	}
+
+	if report := spec.Showback.Report; report != nil {
+		if report.Window == "" {
+			report.Window = MonitoringDefaultShowbackReportWindow
+		}
+		if report.Interval == "" {
+			report.Interval = MonitoringDefaultShowbackReportInterval
+		}
+		if report.Format == "" {
+			report.Format = MonitoringDefaultShowbackReportFormat
+		}
+	}
+
+	if spec.IdleGPU.Threshold == 0 {
+		spec.IdleGPU.Threshold = MonitoringDefaultIdleGPUThreshold
+	}
+	if spec.IdleGPU.Window == "" {
+		spec.IdleGPU.Window = MonitoringDefaultIdleGPUWindow
+	}
+	if spec.IdleGPU.Interval == "" {
+		spec.IdleGPU.Interval = MonitoringDefaultIdleGPUInterval
+	}
+}
+
+//+kubebuilder:webhook:path=/validate-nvidia-addons-rh-ecosystem-edge-io-v1alpha1-monitoring,mutating=false,failurePolicy=fail,sideEffects=None,groups=nvidia.addons.rh-ecosystem-edge.io,resources=monitorings,verbs=create;update,versions=v1alpha1,name=vmonitoring.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Monitoring{}
+
+// ValidateCreate only allows the Monitoring CR named after the addon, which
+// the operator reconciles.
+func (r *Monitoring) ValidateCreate() error {
+	errs := validateSingletonName(&r.ObjectMeta)
+	errs = append(errs, r.validateSpec()...)
+
+	return toInvalidError(monitoringGroupKind, r.Name, errs)
+}
+
+// ValidateUpdate does not hold back the removal of the finalizers of a
+// Monitoring CR being deleted.
+func (r *Monitoring) ValidateUpdate(old runtime.Object) error {
+	if r.DeletionTimestamp != nil {
+		return nil
+	}
+
+	return toInvalidError(monitoringGroupKind, r.Name, r.validateSpec())
+}
+
+func (r *Monitoring) ValidateDelete() error {
+	return nil
+}
+
+func (r *Monitoring) validateSpec() field.ErrorList {
+	errs := field.ErrorList{}
+	spec := field.NewPath("spec")
+
+	errs = append(errs, validateResources(spec.Child("prometheus", "resources"), r.Spec.Prometheus.Resources)...)
+	errs = append(errs, validateResources(spec.Child("prometheus", "kube_rbac_proxy", "resources"),
+		r.Spec.Prometheus.KubeRBACProxy.Resources)...)
+	errs = append(errs, validateVolumeClaimTemplate(spec.Child("prometheus", "volume_claim_template"),
+		r.Spec.Prometheus.VolumeClaimTemplate)...)
+	errs = append(errs, validateResources(spec.Child("alertmanager", "resources"), r.Spec.Alertmanager.Resources)...)
+	errs = append(errs, validateVolumeClaimTemplate(spec.Child("alertmanager", "volume_claim_template"),
+		r.Spec.Alertmanager.VolumeClaimTemplate)...)
+
+	errs = append(errs, validateReceivers(spec.Child("receivers"), r.Spec.Receivers)...)
+	errs = append(errs, validateRoutes(spec.Child("routes"), r.Spec.Routes, r.Spec.Receivers)...)
+	errs = append(errs, validateRemoteWrite(spec.Child("remote_write"), r.Spec.RemoteWrite)...)
+
+	return errs
+}
+
+// validateReceivers checks that the receivers are named uniquely, apart from
+// the built-in receivers, and that each one sends its notifications somewhere.
+func validateReceivers(path *field.Path, receivers []MonitoringReceiver) field.ErrorList {
+	errs := field.ErrorList{}
+	names := map[string]bool{}
+
+	for i, receiver := range receivers {
+		receiverPath := path.Index(i)
+
+		switch receiver.Name {
+		case MonitoringNullReceiverName, MonitoringPagerDutyReceiverName, MonitoringDeadMansSnitchReceiverName:
+			errs = append(errs, field.Invalid(receiverPath.Child("name"), receiver.Name,
+				"the name is reserved for a built-in receiver"))
+		}
+		if names[receiver.Name] {
+			errs = append(errs, field.Duplicate(receiverPath.Child("name"), receiver.Name))
+		}
+		names[receiver.Name] = true
+
+		if receiver.Slack == nil && receiver.Email == nil && receiver.Webhook == nil && receiver.OpsGenie == nil {
+			errs = append(errs, field.Required(receiverPath,
+				"one of slack, email, webhook or opsgenie must be set"))
+		}
+
+		if receiver.Slack != nil {
+			errs = append(errs, validateSecretKeySelector(receiverPath.Child("slack", "api_url"), &receiver.Slack.APIURL)...)
+		}
+		if receiver.Email != nil {
+			errs = append(errs, validateSecretKeySelector(receiverPath.Child("email", "auth_password"), receiver.Email.AuthPassword)...)
+		}
+		if receiver.Webhook != nil {
+			errs = append(errs, validateSecretKeySelector(receiverPath.Child("webhook", "url"), &receiver.Webhook.URL)...)
+		}
+		if receiver.OpsGenie != nil {
+			errs = append(errs, validateSecretKeySelector(receiverPath.Child("opsgenie", "api_key"), &receiver.OpsGenie.APIKey)...)
+		}
+	}
+
+	return errs
+}
+
+// validateRoutes checks that the routes send the alerts to receivers of the
+// MonitoringSpec.
+func validateRoutes(path *field.Path, routes []MonitoringRoute, receivers []MonitoringReceiver) field.ErrorList {
+	errs := field.ErrorList{}
+
+	names := map[string]bool{}
+	for _, receiver := range receivers {
+		names[receiver.Name] = true
+	}
+
+	for i, route := range routes {
+		if !names[route.Receiver] {
+			errs = append(errs, field.NotFound(path.Index(i).Child("receiver"), route.Receiver))
+		}
+	}
+
+	return errs
+}
+
+// validateRemoteWrite checks that the remote write authenticates one way at
+// most, and that a client certificate comes with its key.
+func validateRemoteWrite(path *field.Path, spec *MonitoringRemoteWriteSpec) field.ErrorList {
+	errs := field.ErrorList{}
+	if spec == nil {
+		return errs
+	}
+
+	if spec.BasicAuth != nil && spec.BearerToken != nil {
+		errs = append(errs, field.Forbidden(path.Child("bearer_token"),
+			"basic_auth and bearer_token are mutually exclusive"))
+	}
+	if spec.BasicAuth != nil {
+		errs = append(errs, validateSecretKeySelector(path.Child("basic_auth", "username"), &spec.BasicAuth.Username)...)
+		errs = append(errs, validateSecretKeySelector(path.Child("basic_auth", "password"), &spec.BasicAuth.Password)...)
+	}
+	errs = append(errs, validateSecretKeySelector(path.Child("bearer_token"), spec.BearerToken)...)
+
+	if tls := spec.TLS; tls != nil {
+		if (tls.Cert == nil) != (tls.Key == nil) {
+			errs = append(errs, field.Invalid(path.Child("tls"), "",
+				"cert and key must be set together"))
+		}
+		errs = append(errs, validateSecretKeySelector(path.Child("tls", "ca"), tls.CA)...)
+		errs = append(errs, validateSecretKeySelector(path.Child("tls", "cert"), tls.Cert)...)
+		errs = append(errs, validateSecretKeySelector(path.Child("tls", "key"), tls.Key)...)
+	}
+
+	return errs
+}
+
+// validateSecretKeySelector checks that a Secret key is fully referenced.
+func validateSecretKeySelector(path *field.Path, selector *corev1.SecretKeySelector) field.ErrorList {
+	errs := field.ErrorList{}
+	if selector == nil {
+		return errs
+	}
+
+	if selector.Name == "" {
+		errs = append(errs, field.Required(path.Child("name"), "the Secret name must be set"))
+	}
+	if selector.Key == "" {
+		errs = append(errs, field.Required(path.Child("key"), "the Secret key must be set"))
+	}
+
+	return errs
+}
+
+func validateVolumeClaimTemplate(path *field.Path, template *MonitoringVolumeClaimTemplate) field.ErrorList {
+	if template == nil || template.Size.Sign() > 0 {
+		return nil
+	}
+
+	return field.ErrorList{field.Invalid(path.Child("size"), template.Size.String(), "must be positive")}
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
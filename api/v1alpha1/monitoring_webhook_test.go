@@ -0,0 +1,206 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+func newTestMonitoring(name string) *Monitoring {
+	return &Monitoring{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: common.GlobalConfig.AddonNamespace,
+		},
+	}
+}
+
+func newTestSecretKeySelector(name, key string) corev1.SecretKeySelector {
+	return corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: name},
+		Key:                  key,
+	}
+}
+
+func newTestSlackReceiver(name string) MonitoringReceiver {
+	return MonitoringReceiver{
+		Name: name,
+		Slack: &MonitoringSlackConfig{
+			APIURL: newTestSecretKeySelector("slack", "url"),
+		},
+	}
+}
+
+var _ = Describe("Monitoring webhook", func() {
+	Context("Defaulting", func() {
+		It("should set the defaults of the fields left unset", func() {
+			m := newTestMonitoring(common.GlobalConfig.AddonID)
+			m.Spec.Showback.Report = &MonitoringShowbackReportSpec{}
+			m.Default()
+
+			Expect(m.Spec.Mode).To(Equal(MonitoringModeDedicated))
+			Expect(*m.Spec.Prometheus.Replicas).To(Equal(int32(1)))
+			Expect(*m.Spec.Alertmanager.Replicas).To(Equal(int32(3)))
+			Expect(m.Spec.PagerDuty.GroupBy).To(Equal([]string{"alertname"}))
+			Expect(m.Spec.Showback.Report.Format).To(Equal(MonitoringShowbackReportFormatCSV))
+			Expect(m.Spec.IdleGPU.Threshold).To(Equal(int32(5)))
+		})
+	})
+
+	Context("Validation", func() {
+		It("should accept the Monitoring CR named after the addon", func() {
+			m := newTestMonitoring(common.GlobalConfig.AddonID)
+			m.Spec.Receivers = []MonitoringReceiver{newTestSlackReceiver("team")}
+			m.Spec.Routes = []MonitoringRoute{{Receiver: "team"}}
+
+			Expect(m.ValidateCreate()).To(Succeed())
+		})
+
+		It("should reject another Monitoring CR", func() {
+			Expect(newTestMonitoring("other").ValidateCreate()).To(MatchError(ContainSubstring("metadata.name")))
+		})
+
+		It("should reject the names of the built-in receivers and duplicates", func() {
+			m := newTestMonitoring(common.GlobalConfig.AddonID)
+			m.Spec.Receivers = []MonitoringReceiver{
+				newTestSlackReceiver(MonitoringPagerDutyReceiverName),
+				newTestSlackReceiver("team"),
+				newTestSlackReceiver("team"),
+			}
+
+			err := m.ValidateUpdate(m)
+			Expect(err).To(MatchError(ContainSubstring("spec.receivers[0].name")))
+			Expect(err).To(MatchError(ContainSubstring("spec.receivers[2].name: Duplicate value")))
+		})
+
+		It("should reject a receiver without configuration", func() {
+			m := newTestMonitoring(common.GlobalConfig.AddonID)
+			m.Spec.Receivers = []MonitoringReceiver{{Name: "team"}}
+
+			Expect(m.ValidateUpdate(m)).To(MatchError(ContainSubstring("spec.receivers[0]: Required value")))
+		})
+
+		It("should reject an incomplete Secret reference", func() {
+			m := newTestMonitoring(common.GlobalConfig.AddonID)
+			receiver := newTestSlackReceiver("team")
+			receiver.Slack.APIURL.Key = ""
+			m.Spec.Receivers = []MonitoringReceiver{receiver}
+
+			Expect(m.ValidateUpdate(m)).To(MatchError(ContainSubstring("spec.receivers[0].slack.api_url.key")))
+		})
+
+		It("should reject a route to an undefined receiver", func() {
+			m := newTestMonitoring(common.GlobalConfig.AddonID)
+			m.Spec.Routes = []MonitoringRoute{{Receiver: "team"}}
+
+			Expect(m.ValidateUpdate(m)).To(MatchError(ContainSubstring("spec.routes[0].receiver: Not found")))
+		})
+
+		It("should reject conflicting remote write settings", func() {
+			m := newTestMonitoring(common.GlobalConfig.AddonID)
+			cert := newTestSecretKeySelector("remote-write", "tls.crt")
+			token := newTestSecretKeySelector("remote-write", "token")
+			m.Spec.RemoteWrite = &MonitoringRemoteWriteSpec{
+				URL: "https://metrics.example.com/api/v1/write",
+				BasicAuth: &MonitoringBasicAuth{
+					Username: newTestSecretKeySelector("remote-write", "username"),
+					Password: newTestSecretKeySelector("remote-write", "password"),
+				},
+				BearerToken: &token,
+				TLS:         &MonitoringTLSConfig{Cert: &cert},
+			}
+
+			err := m.ValidateUpdate(m)
+			Expect(err).To(MatchError(ContainSubstring("spec.remote_write.bearer_token")))
+			Expect(err).To(MatchError(ContainSubstring("spec.remote_write.tls")))
+		})
+	})
+
+	DescribeTable("validateSpec",
+		func(mutate func(*MonitoringSpec), fields ...string) {
+			m := newTestMonitoring(common.GlobalConfig.AddonID)
+			m.Default()
+			mutate(&m.Spec)
+
+			invalid := []string{}
+			for _, err := range m.validateSpec() {
+				invalid = append(invalid, err.Field)
+			}
+			Expect(invalid).To(ConsistOf(fields))
+		},
+		Entry("defaulted", func(*MonitoringSpec) {}),
+		Entry("empty volume", func(s *MonitoringSpec) {
+			s.Prometheus.VolumeClaimTemplate = &MonitoringVolumeClaimTemplate{}
+		}, "spec.prometheus.volume_claim_template.size"),
+		Entry("sized volume", func(s *MonitoringSpec) {
+			s.Alertmanager.VolumeClaimTemplate = &MonitoringVolumeClaimTemplate{Size: resource.MustParse("1Gi")}
+		}),
+		Entry("email receiver without password", func(s *MonitoringSpec) {
+			s.Receivers = []MonitoringReceiver{{
+				Name:  "team",
+				Email: &MonitoringEmailConfig{To: "team@example.com", From: "gpu@example.com", Smarthost: "smtp:587"},
+			}}
+		}),
+		Entry("remote write with a client certificate", func(s *MonitoringSpec) {
+			cert := newTestSecretKeySelector("remote-write", "tls.crt")
+			key := newTestSecretKeySelector("remote-write", "tls.key")
+			s.RemoteWrite = &MonitoringRemoteWriteSpec{
+				URL: "https://metrics.example.com/api/v1/write",
+				TLS: &MonitoringTLSConfig{Cert: &cert, Key: &key},
+			}
+		}),
+	)
+
+	Context("Admission", func() {
+		BeforeEach(requireEnvTest)
+
+		AfterEach(func() {
+			m := newTestMonitoring(common.GlobalConfig.AddonID)
+			Expect(k8sClient.Delete(context.TODO(), m)).To(Or(Succeed(), WithTransform(k8serrors.IsNotFound, BeTrue())))
+		})
+
+		It("should validate the Monitoring CR", func() {
+			Expect(k8sClient.Create(context.TODO(), newTestMonitoring("other"))).
+				To(WithTransform(k8serrors.IsInvalid, BeTrue()))
+
+			m := newTestMonitoring(common.GlobalConfig.AddonID)
+			Expect(k8sClient.Create(context.TODO(), m)).To(Succeed())
+
+			created := &Monitoring{}
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{
+				Name:      m.Name,
+				Namespace: m.Namespace,
+			}, created)).To(Succeed())
+			Expect(created.Spec.Mode).To(Equal(MonitoringModeDedicated))
+
+			created.Spec.Routes = []MonitoringRoute{{Receiver: "team"}}
+			Expect(k8sClient.Update(context.TODO(), created)).
+				To(MatchError(ContainSubstring("spec.routes[0].receiver")))
+		})
+	})
+})
@@ -22,7 +22,8 @@ limitations under the License.
 package v1alpha1
 
 import (
-	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -31,7 +32,7 @@ func (in *GPUAddon) DeepCopyInto(out *GPUAddon) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -85,9 +86,49 @@ func (in *GPUAddonList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUAddonResourceStatus) DeepCopyInto(out *GPUAddonResourceStatus) {
+	*out = *in
+	if in.Finalizers != nil {
+		in, out := &in.Finalizers, &out.Finalizers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUAddonResourceStatus.
+func (in *GPUAddonResourceStatus) DeepCopy() *GPUAddonResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUAddonResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GPUAddonSpec) DeepCopyInto(out *GPUAddonSpec) {
 	*out = *in
+	if in.ConsolePluginReplicas != nil {
+		in, out := &in.ConsolePluginReplicas, &out.ConsolePluginReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ConsolePluginResources != nil {
+		in, out := &in.ConsolePluginResources, &out.ConsolePluginResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UninstallTimeout != nil {
+		in, out := &in.UninstallTimeout, &out.UninstallTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.FinalizerTimeout != nil {
+		in, out := &in.FinalizerTimeout, &out.FinalizerTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUAddonSpec.
@@ -105,11 +146,21 @@ func (in *GPUAddonStatus) DeepCopyInto(out *GPUAddonStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.UninstallBlockedBy != nil {
+		in, out := &in.UninstallBlockedBy, &out.UninstallBlockedBy
+		*out = make([]GPUAddonWorkload, len(*in))
+		copy(*out, *in)
+	}
+	if in.Uninstall != nil {
+		in, out := &in.Uninstall, &out.Uninstall
+		*out = new(GPUAddonUninstallStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUAddonStatus.
@@ -122,12 +173,51 @@ func (in *GPUAddonStatus) DeepCopy() *GPUAddonStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUAddonUninstallStatus) DeepCopyInto(out *GPUAddonUninstallStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.FinalizerDeadline.DeepCopyInto(&out.FinalizerDeadline)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]GPUAddonResourceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUAddonUninstallStatus.
+func (in *GPUAddonUninstallStatus) DeepCopy() *GPUAddonUninstallStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUAddonUninstallStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUAddonWorkload) DeepCopyInto(out *GPUAddonWorkload) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUAddonWorkload.
+func (in *GPUAddonWorkload) DeepCopy() *GPUAddonWorkload {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUAddonWorkload)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Monitoring) DeepCopyInto(out *Monitoring) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -149,6 +239,164 @@ func (in *Monitoring) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringAlertmanagerSpec) DeepCopyInto(out *MonitoringAlertmanagerSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VolumeClaimTemplate != nil {
+		in, out := &in.VolumeClaimTemplate, &out.VolumeClaimTemplate
+		*out = new(MonitoringVolumeClaimTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringAlertmanagerSpec.
+func (in *MonitoringAlertmanagerSpec) DeepCopy() *MonitoringAlertmanagerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringAlertmanagerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringBasicAuth) DeepCopyInto(out *MonitoringBasicAuth) {
+	*out = *in
+	in.Username.DeepCopyInto(&out.Username)
+	in.Password.DeepCopyInto(&out.Password)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringBasicAuth.
+func (in *MonitoringBasicAuth) DeepCopy() *MonitoringBasicAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringBasicAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringEmailConfig) DeepCopyInto(out *MonitoringEmailConfig) {
+	*out = *in
+	if in.AuthPassword != nil {
+		in, out := &in.AuthPassword, &out.AuthPassword
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringEmailConfig.
+func (in *MonitoringEmailConfig) DeepCopy() *MonitoringEmailConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringEmailConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringGPUHealthSpec) DeepCopyInto(out *MonitoringGPUHealthSpec) {
+	*out = *in
+	if in.EccDoubleBitErrors != nil {
+		in, out := &in.EccDoubleBitErrors, &out.EccDoubleBitErrors
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringGPUHealthSpec.
+func (in *MonitoringGPUHealthSpec) DeepCopy() *MonitoringGPUHealthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringGPUHealthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringIdleGPUPod) DeepCopyInto(out *MonitoringIdleGPUPod) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringIdleGPUPod.
+func (in *MonitoringIdleGPUPod) DeepCopy() *MonitoringIdleGPUPod {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringIdleGPUPod)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringIdleGPUSpec) DeepCopyInto(out *MonitoringIdleGPUSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringIdleGPUSpec.
+func (in *MonitoringIdleGPUSpec) DeepCopy() *MonitoringIdleGPUSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringIdleGPUSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringIdleGPUStatus) DeepCopyInto(out *MonitoringIdleGPUStatus) {
+	*out = *in
+	in.LastAnalysisTime.DeepCopyInto(&out.LastAnalysisTime)
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]MonitoringIdleGPUPod, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringIdleGPUStatus.
+func (in *MonitoringIdleGPUStatus) DeepCopy() *MonitoringIdleGPUStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringIdleGPUStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringKubeRBACProxySpec) DeepCopyInto(out *MonitoringKubeRBACProxySpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringKubeRBACProxySpec.
+func (in *MonitoringKubeRBACProxySpec) DeepCopy() *MonitoringKubeRBACProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringKubeRBACProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MonitoringList) DeepCopyInto(out *MonitoringList) {
 	*out = *in
@@ -181,9 +429,247 @@ func (in *MonitoringList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringOpsGenieConfig) DeepCopyInto(out *MonitoringOpsGenieConfig) {
+	*out = *in
+	in.APIKey.DeepCopyInto(&out.APIKey)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringOpsGenieConfig.
+func (in *MonitoringOpsGenieConfig) DeepCopy() *MonitoringOpsGenieConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringOpsGenieConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringPagerDutySpec) DeepCopyInto(out *MonitoringPagerDutySpec) {
+	*out = *in
+	if in.GroupBy != nil {
+		in, out := &in.GroupBy, &out.GroupBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringPagerDutySpec.
+func (in *MonitoringPagerDutySpec) DeepCopy() *MonitoringPagerDutySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringPagerDutySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringPrometheusSpec) DeepCopyInto(out *MonitoringPrometheusSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VolumeClaimTemplate != nil {
+		in, out := &in.VolumeClaimTemplate, &out.VolumeClaimTemplate
+		*out = new(MonitoringVolumeClaimTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	in.KubeRBACProxy.DeepCopyInto(&out.KubeRBACProxy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringPrometheusSpec.
+func (in *MonitoringPrometheusSpec) DeepCopy() *MonitoringPrometheusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringPrometheusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringReceiver) DeepCopyInto(out *MonitoringReceiver) {
+	*out = *in
+	if in.Slack != nil {
+		in, out := &in.Slack, &out.Slack
+		*out = new(MonitoringSlackConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Email != nil {
+		in, out := &in.Email, &out.Email
+		*out = new(MonitoringEmailConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(MonitoringWebhookConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OpsGenie != nil {
+		in, out := &in.OpsGenie, &out.OpsGenie
+		*out = new(MonitoringOpsGenieConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringReceiver.
+func (in *MonitoringReceiver) DeepCopy() *MonitoringReceiver {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringReceiver)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringRemoteWriteSpec) DeepCopyInto(out *MonitoringRemoteWriteSpec) {
+	*out = *in
+	if in.BasicAuth != nil {
+		in, out := &in.BasicAuth, &out.BasicAuth
+		*out = new(MonitoringBasicAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BearerToken != nil {
+		in, out := &in.BearerToken, &out.BearerToken
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(MonitoringTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetricsAllowlist != nil {
+		in, out := &in.MetricsAllowlist, &out.MetricsAllowlist
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringRemoteWriteSpec.
+func (in *MonitoringRemoteWriteSpec) DeepCopy() *MonitoringRemoteWriteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringRemoteWriteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringRoute) DeepCopyInto(out *MonitoringRoute) {
+	*out = *in
+	if in.Severities != nil {
+		in, out := &in.Severities, &out.Severities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AlertNames != nil {
+		in, out := &in.AlertNames, &out.AlertNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringRoute.
+func (in *MonitoringRoute) DeepCopy() *MonitoringRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringShowbackReportSpec) DeepCopyInto(out *MonitoringShowbackReportSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringShowbackReportSpec.
+func (in *MonitoringShowbackReportSpec) DeepCopy() *MonitoringShowbackReportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringShowbackReportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringShowbackSpec) DeepCopyInto(out *MonitoringShowbackSpec) {
+	*out = *in
+	if in.Report != nil {
+		in, out := &in.Report, &out.Report
+		*out = new(MonitoringShowbackReportSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringShowbackSpec.
+func (in *MonitoringShowbackSpec) DeepCopy() *MonitoringShowbackSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringShowbackSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringSlackConfig) DeepCopyInto(out *MonitoringSlackConfig) {
+	*out = *in
+	in.APIURL.DeepCopyInto(&out.APIURL)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringSlackConfig.
+func (in *MonitoringSlackConfig) DeepCopy() *MonitoringSlackConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringSlackConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
 	*out = *in
+	in.Prometheus.DeepCopyInto(&out.Prometheus)
+	in.Alertmanager.DeepCopyInto(&out.Alertmanager)
+	in.GPUHealth.DeepCopyInto(&out.GPUHealth)
+	in.PagerDuty.DeepCopyInto(&out.PagerDuty)
+	if in.Receivers != nil {
+		in, out := &in.Receivers, &out.Receivers
+		*out = make([]MonitoringReceiver, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]MonitoringRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RemoteWrite != nil {
+		in, out := &in.RemoteWrite, &out.RemoteWrite
+		*out = new(MonitoringRemoteWriteSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Showback.DeepCopyInto(&out.Showback)
+	out.IdleGPU = in.IdleGPU
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringSpec.
@@ -201,11 +687,16 @@ func (in *MonitoringStatus) DeepCopyInto(out *MonitoringStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.IdleGPUs != nil {
+		in, out := &in.IdleGPUs, &out.IdleGPUs
+		*out = new(MonitoringIdleGPUStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringStatus.
@@ -217,3 +708,70 @@ func (in *MonitoringStatus) DeepCopy() *MonitoringStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringTLSConfig) DeepCopyInto(out *MonitoringTLSConfig) {
+	*out = *in
+	if in.CA != nil {
+		in, out := &in.CA, &out.CA
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cert != nil {
+		in, out := &in.Cert, &out.Cert
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringTLSConfig.
+func (in *MonitoringTLSConfig) DeepCopy() *MonitoringTLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringTLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringVolumeClaimTemplate) DeepCopyInto(out *MonitoringVolumeClaimTemplate) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	out.Size = in.Size.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringVolumeClaimTemplate.
+func (in *MonitoringVolumeClaimTemplate) DeepCopy() *MonitoringVolumeClaimTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringVolumeClaimTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringWebhookConfig) DeepCopyInto(out *MonitoringWebhookConfig) {
+	*out = *in
+	in.URL.DeepCopyInto(&out.URL)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringWebhookConfig.
+func (in *MonitoringWebhookConfig) DeepCopy() *MonitoringWebhookConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringWebhookConfig)
+	in.DeepCopyInto(out)
+	return out
+}
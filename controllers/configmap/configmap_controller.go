@@ -19,10 +19,12 @@ package configmap
 import (
 	"context"
 	"fmt"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -31,12 +33,21 @@ import (
 
 	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
 	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/health"
 )
 
 // ConfigMapReconciler reconciles a ConfigMap object
 type ConfigMapReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// ReconcileTracker reports the outcome of the reconciliations in the
+	// readiness checks and metrics. Not tracked when not set.
+	ReconcileTracker *health.ReconcileTracker
+
+	// Recorder records the Events on the GPUAddon CRs whose removal is held
+	// back by their uninstall policy.
+	Recorder record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups="",namespace=system,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
@@ -46,35 +57,92 @@ type ConfigMapReconciler struct {
 func (r *ConfigMapReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
-	if err := r.deleteGpuAddonCr(ctx, req.Namespace); err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to delete GPUAddon CR: %w", err)
+	cm := &v1.ConfigMap{}
+	if err := r.Get(ctx, req.NamespacedName, cm); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to get ConfigMap %s: %w", req.NamespacedName, err)
+	}
+
+	requeue, err := r.uninstall(ctx, cm)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 
-	logger.Info("Successfully deleted GPUAddon CR")
+	if requeue > 0 {
+		return ctrl.Result{RequeueAfter: requeue}, nil
+	}
+
+	logger.Info("Successfully uninstalled the addon")
 	return ctrl.Result{}, nil
 }
 
-func (r *ConfigMapReconciler) deleteGpuAddonCr(ctx context.Context, namespace string) error {
+// deleteGpuAddonCr deletes the GPUAddon CRs, as allowed by their uninstall
+// policy. The removal of the addon is requested by the creation of the
+// ConfigMap. It returns when to check again while CRs are held back or being
+// deleted.
+func (r *ConfigMapReconciler) deleteGpuAddonCr(ctx context.Context, cm *v1.ConfigMap) (time.Duration, error) {
 	logger := log.FromContext(ctx).WithValues("Reconcile Step", "DeleteGpuAddonCr")
 	logger.Info("Getting GPUAddon CR")
 
 	gpuAddonCrs := addonv1alpha1.GPUAddonList{}
 	if err := r.List(ctx, &gpuAddonCrs); err != nil {
-		return err
+		return 0, err
 	}
 
 	if len(gpuAddonCrs.Items) > 1 {
-		logger.Info(fmt.Sprintf("In namespace %s there are multiple (%v) GPUAddon CRs.", namespace, len(gpuAddonCrs.Items)))
+		logger.Info(fmt.Sprintf("In namespace %s there are multiple (%v) GPUAddon CRs.", cm.Namespace, len(gpuAddonCrs.Items)))
 	}
 
-	for _, addonCr := range gpuAddonCrs.Items {
-		err := r.Delete(ctx, &addonCr)
-		if err != nil || !k8serrors.IsNotFound(err) {
-			return err
+	var workloads []addonv1alpha1.GPUAddonWorkload
+	requeue := time.Duration(0)
+
+	for i := range gpuAddonCrs.Items {
+		addonCr := &gpuAddonCrs.Items[i]
+		if !addonCr.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		if workloads == nil {
+			var err error
+			if workloads, err = r.getGPUWorkloads(ctx); err != nil {
+				return 0, err
+			}
+		}
+
+		decision := getUninstallDecision(addonCr, cm.CreationTimestamp.Time, workloads, time.Now())
+		if !decision.proceed {
+			if err := r.reportUninstallBlocked(ctx, addonCr, decision); err != nil {
+				return 0, err
+			}
+			if requeue == 0 || decision.requeue < requeue {
+				requeue = decision.requeue
+			}
+			continue
+		}
+
+		if len(workloads) > 0 {
+			logger.Info("Removing the GPUAddon CR while pods still use GPUs",
+				"name", addonCr.Name,
+				"reason", decision.reason,
+				"pods", len(workloads))
+			r.Recorder.Event(addonCr, v1.EventTypeWarning, "UninstallProceeding",
+				fmt.Sprintf("Removing the addon while %d pod(s) still use %d GPU(s): %s",
+					len(workloads), decision.gpuCount, decision.reason))
 		}
+
+		err := r.Delete(ctx, addonCr)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return 0, err
+		}
+	}
+
+	if requeue == 0 && len(gpuAddonCrs.Items) > 0 {
+		requeue = uninstallStepCheckInterval
 	}
 
-	return nil
+	return requeue, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -82,7 +150,7 @@ func (r *ConfigMapReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1.ConfigMap{}).
 		WithEventFilter(configMapFilter()).
-		Complete(r)
+		Complete(r.ReconcileTracker.Track("configmap", r))
 }
 
 func configMapFilter() predicate.Predicate {
@@ -4,14 +4,17 @@ import (
 	"context"
 
 	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
 	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned/scheme"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -72,11 +75,14 @@ func newTestConfigReconciler(objs ...runtime.Object) *ConfigMapReconciler {
 	Expect(addonv1alpha1.AddToScheme(s)).ShouldNot(HaveOccurred())
 	Expect(gpuv1.AddToScheme(s)).ShouldNot(HaveOccurred())
 	Expect(nfdv1.AddToScheme(s)).ShouldNot(HaveOccurred())
+	Expect(operatorv1.AddToScheme(s)).ShouldNot(HaveOccurred())
+	Expect(apiextensionsv1.AddToScheme(s)).ShouldNot(HaveOccurred())
 
 	c := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objs...).Build()
 
 	return &ConfigMapReconciler{
-		Client: c,
-		Scheme: s,
+		Client:   c,
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(10),
 	}
 }
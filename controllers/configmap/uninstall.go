@@ -0,0 +1,249 @@
+package configmap
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/controllers/gpuaddon"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+const (
+	// uninstallStepCheckInterval is how often an uninstall step waiting for
+	// resources to be deleted is checked.
+	uninstallStepCheckInterval = 10 * time.Second
+)
+
+// operatorCRDNames are the CRDs of the GPU and NFD operators, removed on
+// uninstall when enabled. They must match the resourceNames of the CRD
+// deletion RBAC below.
+var operatorCRDNames = []string{
+	"clusterpolicies." + gpuv1.GroupVersion.Group,
+	"nodefeaturediscoveries." + nfdv1.GroupVersion.Group,
+}
+
+//+kubebuilder:rbac:groups=nvidia.addons.rh-ecosystem-edge.io,namespace=system,resources=monitorings,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=operators.coreos.com,namespace=system,resources=subscriptions,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=operators.coreos.com,namespace=system,resources=clusterserviceversions,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=delete,resourceNames=clusterpolicies.nvidia.com;nodefeaturediscoveries.nfd.openshift.io
+
+// uninstallStep is a step of the removal of the addon. It returns when to
+// check again while it is not complete yet, or 0 once it is.
+type uninstallStep struct {
+	name string
+	run  func(ctx context.Context, cm *v1.ConfigMap) (time.Duration, error)
+}
+
+// getUninstallSteps returns the steps of the removal of the addon, each
+// step starting once the previous ones are complete. The GPUAddon CRs are
+// removed first so that their finalizer tears down the GPU stack while the
+// operators are still there, and the addon operator is removed last. The NFD
+// operator being an OLM dependency of the addon, the addon Subscription is
+// removed before it, otherwise OLM would install it again.
+func (r *ConfigMapReconciler) getUninstallSteps() []uninstallStep {
+	return []uninstallStep{
+		{name: "GPUAddon", run: r.deleteGpuAddonCr},
+		{name: "Monitoring", run: r.deleteMonitoringCr},
+		{name: "GPU Operator", run: func(ctx context.Context, _ *v1.ConfigMap) (time.Duration, error) {
+			return r.removeOperator(ctx, common.GlobalConfig.GpuCsvNamespace, common.GlobalConfig.GpuCsvPrefix)
+		}},
+		{name: "Addon Subscription", run: func(ctx context.Context, _ *v1.ConfigMap) (time.Duration, error) {
+			return 0, r.deleteSubscriptions(ctx, common.GlobalConfig.AddonNamespace, common.GlobalConfig.AddonID)
+		}},
+		{name: "NFD Operator", run: func(ctx context.Context, _ *v1.ConfigMap) (time.Duration, error) {
+			return r.removeOperator(ctx, common.GlobalConfig.NfdCsvNamespace, common.GlobalConfig.NfdCsvPrefix)
+		}},
+		{name: "CRDs", run: r.removeOperatorCRDs},
+		{name: "Addon Operator", run: r.removeSelfCsv},
+	}
+}
+
+// uninstall runs the steps of the removal of the addon up to the first one
+// not complete yet. It returns when to check again, or 0 once the addon
+// operator CSV has been deleted.
+func (r *ConfigMapReconciler) uninstall(ctx context.Context, cm *v1.ConfigMap) (time.Duration, error) {
+	logger := log.FromContext(ctx)
+
+	for _, step := range r.getUninstallSteps() {
+		requeue, err := step.run(ctx, cm)
+		if err != nil {
+			return 0, fmt.Errorf("failed to uninstall %s: %w", step.name, err)
+		}
+		if requeue > 0 {
+			logger.Info("Uninstall step in progress", "step", step.name, "requeueAfter", requeue)
+			return requeue, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// deleteMonitoringCr deletes the Monitoring CRs, whose finalizer tears down
+// the monitoring stack, and waits for them to be gone.
+func (r *ConfigMapReconciler) deleteMonitoringCr(ctx context.Context, _ *v1.ConfigMap) (time.Duration, error) {
+	monitorings := addonv1alpha1.MonitoringList{}
+	if err := r.List(ctx, &monitorings, client.InNamespace(common.GlobalConfig.AddonNamespace)); err != nil {
+		return 0, fmt.Errorf("failed to list Monitoring CRs: %w", err)
+	}
+
+	for i := range monitorings.Items {
+		m := &monitorings.Items[i]
+		if !m.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		err := r.Delete(ctx, m)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return 0, fmt.Errorf("failed to delete Monitoring CR %s: %w", m.Name, err)
+		}
+	}
+
+	if len(monitorings.Items) > 0 {
+		return uninstallStepCheckInterval, nil
+	}
+
+	return 0, nil
+}
+
+// removeOperator removes the Subscriptions of an operator, so that OLM does
+// not install it again, then its CSV.
+func (r *ConfigMapReconciler) removeOperator(ctx context.Context, namespace string, csvPrefix string) (time.Duration, error) {
+	if err := r.deleteSubscriptions(ctx, namespace, csvPrefix); err != nil {
+		return 0, err
+	}
+
+	csv, err := common.GetCsvWithPrefix(r.Client, namespace, csvPrefix)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get CSV %s: %w", csvPrefix, err)
+	}
+
+	if csv.DeletionTimestamp.IsZero() {
+		err = r.Delete(ctx, csv)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return 0, fmt.Errorf("failed to delete CSV %s: %w", csv.Name, err)
+		}
+	}
+
+	return uninstallStepCheckInterval, nil
+}
+
+// deleteSubscriptions deletes the Subscriptions to a package, which leaves
+// the installed CSV in place.
+func (r *ConfigMapReconciler) deleteSubscriptions(ctx context.Context, namespace string, pkg string) error {
+	subscriptions := operatorsv1alpha1.SubscriptionList{}
+	if err := r.List(ctx, &subscriptions, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("failed to list Subscriptions in %s: %w", namespace, err)
+	}
+
+	for i := range subscriptions.Items {
+		s := &subscriptions.Items[i]
+		if s.Spec == nil || s.Spec.Package != pkg {
+			continue
+		}
+
+		err := r.Delete(ctx, s)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete Subscription %s: %w", s.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// removeOperatorCRDs removes the CRDs of the GPU and NFD operators when
+// enabled, once the operators are gone.
+func (r *ConfigMapReconciler) removeOperatorCRDs(ctx context.Context, _ *v1.ConfigMap) (time.Duration, error) {
+	if !common.GlobalConfig.UninstallRemoveCRDs {
+		return 0, nil
+	}
+
+	crds := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := r.List(ctx, crds); err != nil {
+		return 0, fmt.Errorf("failed to list the CRDs: %w", err)
+	}
+
+	remaining := 0
+	for i := range crds.Items {
+		crd := &crds.Items[i]
+		if !common.SliceContainsString(operatorCRDNames, crd.Name) {
+			continue
+		}
+		remaining++
+
+		if !crd.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		err := r.Delete(ctx, crd)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return 0, fmt.Errorf("failed to delete CRD %s: %w", crd.Name, err)
+		}
+	}
+
+	if remaining > 0 {
+		return uninstallStepCheckInterval, nil
+	}
+
+	return 0, nil
+}
+
+// removeSelfCsv deletes the addon operator CSV, after a last update of the
+// uninstall report.
+func (r *ConfigMapReconciler) removeSelfCsv(ctx context.Context, _ *v1.ConfigMap) (time.Duration, error) {
+	logger := log.FromContext(ctx).WithValues("Reconcile Step", "Addon CSV Deletion")
+
+	addonCsv, err := common.GetCsvWithPrefix(r.Client, common.GlobalConfig.AddonNamespace, common.GlobalConfig.AddonID)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	// The report is informational, it does not hold back the removal.
+	if err := gpuaddon.RefreshUninstallReport(ctx, r.Client, r.Recorder); err != nil {
+		logger.Error(err, "Failed to update the uninstall report")
+	}
+
+	logger.Info("Cleanup Reconcile | Delete own CSV")
+
+	err = r.Delete(ctx, addonCsv)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return 0, fmt.Errorf("failed to delete GPUAddon Operator CSV %s: %w", addonCsv.Name, err)
+	}
+
+	return 0, nil
+}
+
+// IsUninstallRequested tells whether the removal of the addon has been
+// requested, i.e. the ConfigMap named after the addon exists.
+func IsUninstallRequested(ctx context.Context, c client.Reader) (bool, error) {
+	err := c.Get(ctx, types.NamespacedName{
+		Name:      common.GlobalConfig.AddonID,
+		Namespace: common.GlobalConfig.AddonNamespace,
+	}, &v1.ConfigMap{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get ConfigMap %s: %w", common.GlobalConfig.AddonID, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,214 @@
+package configmap
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+const (
+	gpuResourceName v1.ResourceName = "nvidia.com/gpu"
+
+	// uninstallDrainCheckInterval is how often the pods using GPUs are
+	// checked while they hold back the removal of the addon.
+	uninstallDrainCheckInterval = time.Minute
+
+	// maxUninstallBlockedByReported bounds the size of the GPUAddon status.
+	maxUninstallBlockedByReported = 50
+)
+
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// uninstallDecision tells whether a GPUAddon CR can be removed, and if not
+// when to check again.
+type uninstallDecision struct {
+	proceed    bool
+	requeue    time.Duration
+	reason     string
+	workloads  []addonv1alpha1.GPUAddonWorkload
+	gpuCount   int64
+	policy     addonv1alpha1.GPUAddonUninstallPolicy
+	deadline   time.Time
+	hasTimeout bool
+}
+
+// getGPUWorkloads returns the running pods requesting GPUs, outside of the
+// addon namespace where the GPU operator operands run.
+func (r *ConfigMapReconciler) getGPUWorkloads(ctx context.Context) ([]addonv1alpha1.GPUAddonWorkload, error) {
+	pods := &v1.PodList{}
+	if err := r.List(ctx, pods); err != nil {
+		return nil, fmt.Errorf("failed to list the pods: %w", err)
+	}
+
+	workloads := []addonv1alpha1.GPUAddonWorkload{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Namespace == common.GlobalConfig.AddonNamespace ||
+			pod.Status.Phase == v1.PodSucceeded ||
+			pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+
+		gpus := getPodGPUs(pod)
+		if gpus == 0 {
+			continue
+		}
+
+		workloads = append(workloads, addonv1alpha1.GPUAddonWorkload{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			GPUs:      gpus,
+		})
+	}
+
+	sort.Slice(workloads, func(i, j int) bool {
+		if workloads[i].Namespace != workloads[j].Namespace {
+			return workloads[i].Namespace < workloads[j].Namespace
+		}
+		return workloads[i].Name < workloads[j].Name
+	})
+
+	return workloads, nil
+}
+
+// getPodGPUs returns the GPUs allocated to a pod, i.e. those of its
+// containers, or of its largest init container if greater.
+func getPodGPUs(pod *v1.Pod) int64 {
+	containerGPUs := func(c *v1.Container) int64 {
+		if limit, ok := c.Resources.Limits[gpuResourceName]; ok {
+			return limit.Value()
+		}
+		if request, ok := c.Resources.Requests[gpuResourceName]; ok {
+			return request.Value()
+		}
+		return 0
+	}
+
+	gpus := int64(0)
+	for i := range pod.Spec.Containers {
+		gpus += containerGPUs(&pod.Spec.Containers[i])
+	}
+	for i := range pod.Spec.InitContainers {
+		if init := containerGPUs(&pod.Spec.InitContainers[i]); init > gpus {
+			gpus = init
+		}
+	}
+
+	return gpus
+}
+
+// getUninstallDecision applies the uninstall policy of a GPUAddon CR, the
+// removal of the addon having been requested at the given time.
+func getUninstallDecision(
+	gpuAddon *addonv1alpha1.GPUAddon,
+	requestedAt time.Time,
+	workloads []addonv1alpha1.GPUAddonWorkload,
+	now time.Time) uninstallDecision {
+
+	policy := gpuAddon.Spec.UninstallPolicy
+	if policy == "" {
+		policy = addonv1alpha1.GPUAddonDefaultUninstallPolicy
+	}
+
+	decision := uninstallDecision{
+		workloads: workloads,
+		policy:    policy,
+	}
+	for _, w := range workloads {
+		decision.gpuCount += w.GPUs
+	}
+
+	switch {
+	case gpuAddon.Annotations[addonv1alpha1.GPUAddonForceUninstallAnnotation] == "true":
+		decision.proceed = true
+		decision.reason = "Forced"
+		return decision
+	case len(workloads) == 0:
+		decision.proceed = true
+		decision.reason = "NoGPUWorkloads"
+		return decision
+	case policy == addonv1alpha1.GPUAddonUninstallPolicyImmediate:
+		decision.proceed = true
+		decision.reason = "Immediate"
+		return decision
+	case policy == addonv1alpha1.GPUAddonUninstallPolicyBlock:
+		decision.requeue = uninstallDrainCheckInterval
+		decision.reason = "GPUWorkloadsRunning"
+		return decision
+	}
+
+	timeout := addonv1alpha1.GPUAddonDefaultUninstallTimeout
+	if gpuAddon.Spec.UninstallTimeout != nil {
+		timeout = gpuAddon.Spec.UninstallTimeout.Duration
+	}
+	decision.hasTimeout = true
+	decision.deadline = requestedAt.Add(timeout)
+
+	remaining := decision.deadline.Sub(now)
+	if remaining <= 0 {
+		decision.proceed = true
+		decision.reason = "TimeoutExpired"
+		return decision
+	}
+
+	decision.requeue = uninstallDrainCheckInterval
+	if remaining < decision.requeue {
+		decision.requeue = remaining
+	}
+	decision.reason = "GPUWorkloadsRunning"
+
+	return decision
+}
+
+func (d uninstallDecision) message() string {
+	message := fmt.Sprintf("%d pod(s) using %d GPU(s) hold back the removal of the addon (policy %s)",
+		len(d.workloads), d.gpuCount, d.policy)
+	if d.hasTimeout {
+		message += fmt.Sprintf(", removing it anyway at %s", d.deadline.UTC().Format(time.RFC3339))
+	}
+	return message
+}
+
+// reportUninstallBlocked reports the pods holding back the removal of a
+// GPUAddon CR in its status, and in an Event whenever they change.
+func (r *ConfigMapReconciler) reportUninstallBlocked(
+	ctx context.Context,
+	gpuAddon *addonv1alpha1.GPUAddon,
+	decision uninstallDecision) error {
+
+	message := decision.message()
+
+	previous := meta.FindStatusCondition(gpuAddon.Status.Conditions, addonv1alpha1.GPUAddonUninstallBlockedCondition)
+	if previous == nil || previous.Status != metav1.ConditionTrue || previous.Message != message {
+		r.Recorder.Event(gpuAddon, v1.EventTypeWarning, "UninstallBlocked", message)
+	}
+
+	blockedBy := decision.workloads
+	if len(blockedBy) > maxUninstallBlockedByReported {
+		blockedBy = blockedBy[:maxUninstallBlockedByReported]
+	}
+
+	patch := client.MergeFrom(gpuAddon.DeepCopy())
+	meta.SetStatusCondition(&gpuAddon.Status.Conditions, common.NewCondition(
+		addonv1alpha1.GPUAddonUninstallBlockedCondition,
+		metav1.ConditionTrue,
+		decision.reason,
+		message))
+	gpuAddon.Status.UninstallBlockedBy = blockedBy
+
+	if err := r.Status().Patch(ctx, gpuAddon, patch); err != nil {
+		return fmt.Errorf("failed to patch status: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package configmap
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+var _ = Describe("Uninstall policy", func() {
+	common.ProcessConfig()
+
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      common.GlobalConfig.AddonID,
+			Namespace: common.GlobalConfig.AddonNamespace,
+		},
+	}
+
+	newConfigMap := func(requestedAt time.Time) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              common.GlobalConfig.AddonID,
+				Namespace:         common.GlobalConfig.AddonNamespace,
+				CreationTimestamp: metav1.NewTime(requestedAt),
+			},
+		}
+	}
+
+	newGPUAddon := func(policy addonv1alpha1.GPUAddonUninstallPolicy) *addonv1alpha1.GPUAddon {
+		return &addonv1alpha1.GPUAddon{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "addon",
+				Namespace: common.GlobalConfig.AddonNamespace,
+			},
+			Spec: addonv1alpha1.GPUAddonSpec{
+				UninstallPolicy: policy,
+			},
+		}
+	}
+
+	newGPUPod := func(namespace, name string, gpus string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name: "main",
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							gpuResourceName: resource.MustParse(gpus),
+						},
+					},
+				}},
+			},
+			Status: corev1.PodStatus{
+				Phase: phase,
+			},
+		}
+	}
+
+	isDeleted := func(r *ConfigMapReconciler, g *addonv1alpha1.GPUAddon) bool {
+		err := r.Get(context.TODO(), gpuAddonKey(g), &addonv1alpha1.GPUAddon{})
+		if k8serrors.IsNotFound(err) {
+			return true
+		}
+		Expect(err).ShouldNot(HaveOccurred())
+		return false
+	}
+
+	It("should only count the running pods using GPUs outside of the addon namespace", func() {
+		r := newTestConfigReconciler(
+			newGPUPod("team-a", "trainer", "2", corev1.PodRunning),
+			newGPUPod("team-a", "done", "1", corev1.PodSucceeded),
+			newGPUPod(common.GlobalConfig.AddonNamespace, "validator", "1", corev1.PodRunning),
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cpu", Namespace: "team-a"}},
+		)
+
+		workloads, err := r.getGPUWorkloads(context.TODO())
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(workloads).To(Equal([]addonv1alpha1.GPUAddonWorkload{
+			{Namespace: "team-a", Name: "trainer", GPUs: 2},
+		}))
+	})
+
+	It("should remove the addon right away without pods using GPUs", func() {
+		g := newGPUAddon(addonv1alpha1.GPUAddonUninstallPolicyBlock)
+		r := newTestConfigReconciler(newConfigMap(time.Now()), g)
+
+		_, err := r.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(isDeleted(r, g)).To(BeTrue())
+	})
+
+	It("should remove the addon right away with the Immediate policy", func() {
+		g := newGPUAddon(addonv1alpha1.GPUAddonUninstallPolicyImmediate)
+		r := newTestConfigReconciler(newConfigMap(time.Now()), g,
+			newGPUPod("team-a", "trainer", "2", corev1.PodRunning))
+
+		_, err := r.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(isDeleted(r, g)).To(BeTrue())
+	})
+
+	It("should wait for the pods using GPUs and report them", func() {
+		g := newGPUAddon(addonv1alpha1.GPUAddonUninstallPolicyWaitForDrain)
+		r := newTestConfigReconciler(newConfigMap(time.Now()), g,
+			newGPUPod("team-a", "trainer", "2", corev1.PodRunning))
+		recorder := r.Recorder.(*record.FakeRecorder)
+
+		res, err := r.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.RequeueAfter).To(Equal(uninstallDrainCheckInterval))
+		Expect(isDeleted(r, g)).To(BeFalse())
+
+		updated := &addonv1alpha1.GPUAddon{}
+		Expect(r.Get(context.TODO(), gpuAddonKey(g), updated)).To(Succeed())
+		Expect(updated.Status.UninstallBlockedBy).To(Equal([]addonv1alpha1.GPUAddonWorkload{
+			{Namespace: "team-a", Name: "trainer", GPUs: 2},
+		}))
+		condition := meta.FindStatusCondition(updated.Status.Conditions, addonv1alpha1.GPUAddonUninstallBlockedCondition)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring("1 pod(s) using 2 GPU(s)"))
+
+		Expect(recorder.Events).To(HaveLen(1))
+		Expect(<-recorder.Events).To(HavePrefix("Warning UninstallBlocked"))
+
+		// The Event is not repeated while the pods are unchanged.
+		_, err = r.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("should remove the addon once the uninstall timeout expired", func() {
+		g := newGPUAddon(addonv1alpha1.GPUAddonUninstallPolicyWaitForDrain)
+		g.Spec.UninstallTimeout = &metav1.Duration{Duration: 30 * time.Minute}
+		r := newTestConfigReconciler(newConfigMap(time.Now().Add(-time.Hour)), g,
+			newGPUPod("team-a", "trainer", "2", corev1.PodRunning))
+		recorder := r.Recorder.(*record.FakeRecorder)
+
+		_, err := r.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(isDeleted(r, g)).To(BeTrue())
+		Expect(<-recorder.Events).To(ContainSubstring("TimeoutExpired"))
+	})
+
+	It("should block the removal indefinitely with the Block policy", func() {
+		g := newGPUAddon(addonv1alpha1.GPUAddonUninstallPolicyBlock)
+		r := newTestConfigReconciler(newConfigMap(time.Now().Add(-30*24*time.Hour)), g,
+			newGPUPod("team-a", "trainer", "2", corev1.PodRunning))
+
+		res, err := r.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.RequeueAfter).To(Equal(uninstallDrainCheckInterval))
+		Expect(isDeleted(r, g)).To(BeFalse())
+	})
+
+	It("should remove the addon regardless of the policy when forced", func() {
+		g := newGPUAddon(addonv1alpha1.GPUAddonUninstallPolicyBlock)
+		g.Annotations = map[string]string{addonv1alpha1.GPUAddonForceUninstallAnnotation: "true"}
+		r := newTestConfigReconciler(newConfigMap(time.Now()), g,
+			newGPUPod("team-a", "trainer", "2", corev1.PodRunning))
+
+		_, err := r.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(isDeleted(r, g)).To(BeTrue())
+	})
+
+	It("should check again no later than the uninstall deadline", func() {
+		g := newGPUAddon("")
+		now := time.Now()
+
+		decision := getUninstallDecision(g, now.Add(-time.Hour+20*time.Second), []addonv1alpha1.GPUAddonWorkload{
+			{Namespace: "team-a", Name: "trainer", GPUs: 1},
+		}, now)
+		Expect(decision.proceed).To(BeFalse())
+		Expect(decision.policy).To(Equal(addonv1alpha1.GPUAddonUninstallPolicyWaitForDrain))
+		Expect(decision.requeue).To(Equal(20 * time.Second))
+	})
+})
+
+func gpuAddonKey(g *addonv1alpha1.GPUAddon) types.NamespacedName {
+	return types.NamespacedName{Name: g.Name, Namespace: g.Namespace}
+}
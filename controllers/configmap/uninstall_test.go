@@ -0,0 +1,160 @@
+package configmap
+
+import (
+	"context"
+
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+var _ = Describe("Uninstall", func() {
+	common.ProcessConfig()
+
+	ns := common.GlobalConfig.AddonNamespace
+
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      common.GlobalConfig.AddonID,
+			Namespace: ns,
+		},
+	}
+
+	newSubscription := func(name string, pkg string) *operatorsv1alpha1.Subscription {
+		return &operatorsv1alpha1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+			Spec:       &operatorsv1alpha1.SubscriptionSpec{Package: pkg},
+		}
+	}
+
+	newCRD := func(name string, group string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Group: group},
+		}
+	}
+
+	exists := func(r *ConfigMapReconciler, obj client.Object) bool {
+		err := r.Get(context.TODO(), client.ObjectKeyFromObject(obj), obj)
+		if k8serrors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).ShouldNot(HaveOccurred())
+		return true
+	}
+
+	reconcileOnce := func(r *ConfigMapReconciler) reconcile.Result {
+		res, err := r.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+		return res
+	}
+
+	It("should remove the addon step by step, its own CSV last", func() {
+		gpuAddon := &addonv1alpha1.GPUAddon{
+			ObjectMeta: metav1.ObjectMeta{Name: common.GlobalConfig.AddonID, Namespace: ns},
+		}
+		monitoring := &addonv1alpha1.Monitoring{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       common.GlobalConfig.AddonID,
+				Namespace:  ns,
+				Finalizers: []string{common.GlobalConfig.AddonID},
+			},
+		}
+		gpuSubscription := newSubscription("gpu-operator-certified", common.GlobalConfig.GpuCsvPrefix)
+		gpuCsv := common.NewCsv(ns, common.GlobalConfig.GpuCsvPrefix+".v1.10.1", "")
+		nfdSubscription := newSubscription("nfd-4.10-redhat-operators", common.GlobalConfig.NfdCsvPrefix)
+		nfdCsv := common.NewCsv(ns, common.GlobalConfig.NfdCsvPrefix+".4.10.0", "")
+		addonSubscription := newSubscription("addon-"+common.GlobalConfig.AddonID, common.GlobalConfig.AddonID)
+		addonCsv := common.NewCsv(ns, common.GlobalConfig.AddonID+".v1.2.0", "")
+		crd := newCRD("clusterpolicies.nvidia.com", "nvidia.com")
+
+		r := newTestConfigReconciler(
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: common.GlobalConfig.AddonID, Namespace: ns}},
+			gpuAddon, monitoring, gpuSubscription, gpuCsv, nfdSubscription, nfdCsv, addonSubscription, addonCsv, crd)
+
+		By("removing the GPUAddon CR first")
+		Expect(reconcileOnce(r).RequeueAfter).To(Equal(uninstallStepCheckInterval))
+		Expect(exists(r, gpuAddon)).To(BeFalse())
+		Expect(exists(r, monitoring)).To(BeTrue())
+		Expect(monitoring.DeletionTimestamp).To(BeNil())
+
+		By("waiting for the Monitoring CR finalizer")
+		Expect(reconcileOnce(r).RequeueAfter).To(Equal(uninstallStepCheckInterval))
+		Expect(exists(r, monitoring)).To(BeTrue())
+		Expect(monitoring.DeletionTimestamp).ToNot(BeNil())
+		Expect(exists(r, gpuCsv)).To(BeTrue())
+
+		monitoring.Finalizers = nil
+		Expect(r.Update(context.TODO(), monitoring)).To(Succeed())
+
+		By("removing the GPU operator, then the NFD operator once OLM no longer resolves it for the addon")
+		Expect(reconcileOnce(r).RequeueAfter).To(Equal(uninstallStepCheckInterval))
+		Expect(exists(r, gpuSubscription)).To(BeFalse())
+		Expect(exists(r, gpuCsv)).To(BeFalse())
+		Expect(exists(r, addonSubscription)).To(BeTrue())
+		Expect(exists(r, nfdCsv)).To(BeTrue())
+
+		Expect(reconcileOnce(r).RequeueAfter).To(Equal(uninstallStepCheckInterval))
+		Expect(exists(r, nfdSubscription)).To(BeFalse())
+		Expect(exists(r, nfdCsv)).To(BeFalse())
+		Expect(exists(r, addonSubscription)).To(BeFalse())
+		Expect(exists(r, addonCsv)).To(BeTrue())
+
+		By("removing its own CSV last, keeping the CRDs by default")
+		Expect(reconcileOnce(r).RequeueAfter).To(BeZero())
+		Expect(exists(r, addonCsv)).To(BeFalse())
+		Expect(exists(r, crd)).To(BeTrue())
+
+		report := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "gpuaddon-uninstall-report", Namespace: ns}}
+		Expect(exists(r, report)).To(BeTrue())
+		Expect(report.Data["report.json"]).To(ContainSubstring("clusterpolicies.nvidia.com"))
+	})
+
+	It("should remove the GPU and NFD operator CRDs when enabled", func() {
+		common.GlobalConfig.UninstallRemoveCRDs = true
+		DeferCleanup(func() {
+			common.GlobalConfig.UninstallRemoveCRDs = false
+		})
+
+		gpuCRD := newCRD("clusterpolicies.nvidia.com", "nvidia.com")
+		nfdCRD := newCRD("nodefeaturediscoveries.nfd.openshift.io", "nfd.openshift.io")
+		otherCRD := newCRD("foos.example.com", "example.com")
+		// Not covered by the CRD deletion RBAC.
+		otherGPUCRD := newCRD("nvidiadrivers.nvidia.com", "nvidia.com")
+		addonCsv := common.NewCsv(ns, common.GlobalConfig.AddonID+".v1.2.0", "")
+
+		r := newTestConfigReconciler(
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: common.GlobalConfig.AddonID, Namespace: ns}},
+			gpuCRD, nfdCRD, otherCRD, otherGPUCRD, addonCsv)
+
+		Expect(reconcileOnce(r).RequeueAfter).To(Equal(uninstallStepCheckInterval))
+		Expect(exists(r, gpuCRD)).To(BeFalse())
+		Expect(exists(r, nfdCRD)).To(BeFalse())
+		Expect(exists(r, otherCRD)).To(BeTrue())
+		Expect(exists(r, otherGPUCRD)).To(BeTrue())
+		Expect(exists(r, addonCsv)).To(BeTrue())
+
+		Expect(reconcileOnce(r).RequeueAfter).To(BeZero())
+		Expect(exists(r, addonCsv)).To(BeFalse())
+	})
+
+	It("should tell whether the uninstall was requested", func() {
+		r := newTestConfigReconciler()
+		Expect(IsUninstallRequested(context.TODO(), r.Client)).To(BeFalse())
+
+		r = newTestConfigReconciler(
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: common.GlobalConfig.AddonID, Namespace: ns}})
+		Expect(IsUninstallRequested(context.TODO(), r.Client)).To(BeTrue())
+	})
+})
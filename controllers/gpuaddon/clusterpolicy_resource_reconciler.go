@@ -63,6 +63,13 @@ func (r *ClusterPolicyResourceReconciler) Reconcile(
 		return conditions, err
 	}
 
+	if cp.Status.State == gpuv1.Ready {
+		ClusterPolicyReady.WithLabelValues().Set(1)
+	} else {
+		ClusterPolicyReady.WithLabelValues().Set(0)
+	}
+	setClusterPolicyStateMetric(cp.Status.State)
+
 	conditions = append(conditions, r.getDeployedConditionCreateSuccess())
 
 	logger.Info("ClusterPolicy reconciled successfully",
@@ -160,6 +167,16 @@ func (r *ClusterPolicyResourceReconciler) Delete(ctx context.Context, c client.C
 	return false, nil
 }
 
+func (r *ClusterPolicyResourceReconciler) Owned(ctx context.Context, c client.Client) ([]client.Object, error) {
+	return []client.Object{
+		&gpuv1.ClusterPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: common.GlobalConfig.ClusterPolicyName,
+			},
+		},
+	}, nil
+}
+
 func (r *ClusterPolicyResourceReconciler) getDeployedConditionFetchFailed() metav1.Condition {
 	return common.NewCondition(
 		ClusterPolicyDeployedCondition,
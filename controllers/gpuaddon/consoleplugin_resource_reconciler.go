@@ -25,9 +25,13 @@ import (
 	operatorv1 "github.com/openshift/api/operator/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
@@ -45,11 +49,41 @@ const (
 
 	consolePluginName = "console-plugin-nvidia-gpu"
 
+	consolePluginDisplayName = "Console Plugin NVIDIA GPU Template"
+
+	consolePluginPort = 9443
+
+	// consolePluginPrometheusProxyAlias is exposed by the console backend as
+	// /api/proxy/plugin/console-plugin-nvidia-gpu/prometheus/.
+	consolePluginPrometheusProxyAlias = "prometheus"
+
+	// consolePluginLegacyI18nAnnotation is how v1alpha1 ConsolePlugins opted
+	// into preloading their localization resources. It is replaced by
+	// spec.i18n.loadType in console.openshift.io/v1.
+	consolePluginLegacyI18nAnnotation = "console.openshift.io/use-i18n"
+
 	ocpVersion4_10 = "4.10"
 )
 
+var (
+	consolePluginGroupKind = schema.GroupKind{
+		Group: consolev1alpha1.GroupName,
+		Kind:  "ConsolePlugin",
+	}
+
+	consolePluginV1 = consolePluginGroupKind.WithVersion("v1")
+)
+
 type ConsolePluginResourceReconciler struct{}
 
+// consolePluginProxyService is the Service the console backend proxies the
+// Prometheus queries of the plugin to.
+type consolePluginProxyService struct {
+	name      string
+	namespace string
+	port      int32
+}
+
 var _ ResourceReconciler = &ConsolePluginResourceReconciler{}
 
 func (r *ConsolePluginResourceReconciler) Reconcile(
@@ -67,7 +101,8 @@ func (r *ConsolePluginResourceReconciler) Reconcile(
 	}
 
 	if !supported {
-		conditions = append(conditions, r.getDeployedConditionNotSupported())
+		conditions = append(conditions, r.getDeployedConditionNotSupported(
+			"ConsolePlugin is not supported when OpenShift version <4.10"))
 
 		logger.Info("ConsolePlugin will not be reconciled as OpenShift version is <4.10",
 			"name", consolePluginName,
@@ -93,7 +128,30 @@ func (r *ConsolePluginResourceReconciler) Reconcile(
 		return conditions, nil
 	}
 
-	if err := r.reconcileConsolePluginDeployment(ctx, client, gpuAddon); err != nil {
+	apiVersion, err := getConsolePluginAPIVersion(client)
+	if err != nil {
+		if !meta.IsNoMatchError(err) {
+			conditions = append(conditions, r.getDeployedConditionFailed(err))
+			return conditions, err
+		}
+
+		conditions = append(conditions, r.getDeployedConditionNotSupported(
+			"ConsolePlugin API is not served by the cluster"))
+
+		logger.Info("ConsolePlugin will not be reconciled as the ConsolePlugin API is not served",
+			"name", consolePluginName,
+			"namespace", gpuAddon.Namespace)
+
+		return conditions, nil
+	}
+
+	dp, err := r.reconcileConsolePluginDeployment(ctx, client, gpuAddon)
+	if err != nil {
+		conditions = append(conditions, r.getDeployedConditionFailed(err))
+		return conditions, err
+	}
+
+	if err := r.reconcileConsolePluginPodDisruptionBudget(ctx, client, gpuAddon); err != nil {
 		conditions = append(conditions, r.getDeployedConditionFailed(err))
 		return conditions, err
 	}
@@ -103,7 +161,13 @@ func (r *ConsolePluginResourceReconciler) Reconcile(
 		return conditions, err
 	}
 
-	if err := r.reconcileConsolePluginCR(ctx, client, gpuAddon); err != nil {
+	proxyService, err := getConsolePluginProxyService(ctx, client, gpuAddon)
+	if err != nil {
+		conditions = append(conditions, r.getDeployedConditionFailed(err))
+		return conditions, err
+	}
+
+	if err := r.reconcileConsolePluginCR(ctx, client, gpuAddon, apiVersion, proxyService); err != nil {
 		conditions = append(conditions, r.getDeployedConditionFailed(err))
 		return conditions, err
 	}
@@ -113,6 +177,11 @@ func (r *ConsolePluginResourceReconciler) Reconcile(
 		return conditions, err
 	}
 
+	if !isDeploymentAvailable(dp) {
+		conditions = append(conditions, r.getDeployedConditionUnavailable(dp))
+		return conditions, nil
+	}
+
 	conditions = append(conditions, r.getDeployedConditionSuccess())
 
 	return conditions, nil
@@ -120,7 +189,7 @@ func (r *ConsolePluginResourceReconciler) Reconcile(
 
 func (r *ConsolePluginResourceReconciler) Delete(ctx context.Context, c client.Client) (bool, error) {
 	var err error
-	deleted := make([]bool, 3)
+	deleted := make([]bool, 4)
 
 	deleted[0], err = r.deleteConsolePluginCR(ctx, c)
 	if err != nil {
@@ -132,7 +201,12 @@ func (r *ConsolePluginResourceReconciler) Delete(ctx context.Context, c client.C
 		return false, err
 	}
 
-	deleted[2], err = r.deleteConsolePluginDeployment(ctx, c)
+	deleted[2], err = r.deleteConsolePluginPodDisruptionBudget(ctx, c)
+	if err != nil {
+		return false, err
+	}
+
+	deleted[3], err = r.deleteConsolePluginDeployment(ctx, c)
 	if err != nil {
 		return false, err
 	}
@@ -146,34 +220,49 @@ func (r *ConsolePluginResourceReconciler) Delete(ctx context.Context, c client.C
 	return true, nil
 }
 
+func (r *ConsolePluginResourceReconciler) Owned(ctx context.Context, c client.Client) ([]client.Object, error) {
+	key := metav1.ObjectMeta{
+		Namespace: common.GlobalConfig.AddonNamespace,
+		Name:      consolePluginName,
+	}
+
+	owned := []client.Object{
+		&corev1.Service{ObjectMeta: key},
+		&policyv1.PodDisruptionBudget{ObjectMeta: key},
+		&appsv1.Deployment{ObjectMeta: key},
+	}
+
+	apiVersion, err := getConsolePluginAPIVersion(c)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return owned, nil
+		}
+		return nil, err
+	}
+
+	return append([]client.Object{newConsolePlugin(apiVersion)}, owned...), nil
+}
+
 func (r *ConsolePluginResourceReconciler) reconcileConsolePluginCR(
 	ctx context.Context,
 	c client.Client,
-	gpuAddon *addonv1alpha1.GPUAddon) error {
+	gpuAddon *addonv1alpha1.GPUAddon,
+	apiVersion string,
+	proxyService consolePluginProxyService) error {
 
 	logger := log.FromContext(ctx, "Reconcile Step", "ConsolePlugin CR")
-	existingCP := &consolev1alpha1.ConsolePlugin{}
-	err := c.Get(ctx, client.ObjectKey{
-		Name: consolePluginName,
-	}, existingCP)
 
-	exists := !k8serrors.IsNotFound(err)
-	if err != nil && !k8serrors.IsNotFound(err) {
-		return err
-	}
-
-	cp := &consolev1alpha1.ConsolePlugin{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: consolePluginName,
-		},
-	}
-
-	if exists {
-		cp = existingCP
-	}
+	cp := newConsolePlugin(apiVersion)
 
-	res, err := controllerutil.CreateOrPatch(context.TODO(), c, cp, func() error {
-		return r.setDesiredConsolePlugin(cp, gpuAddon)
+	res, err := controllerutil.CreateOrPatch(ctx, c, cp, func() error {
+		switch obj := cp.(type) {
+		case *consolev1alpha1.ConsolePlugin:
+			return r.setDesiredConsolePlugin(obj, gpuAddon, proxyService)
+		case *unstructured.Unstructured:
+			return r.setDesiredConsolePluginV1(obj, gpuAddon, proxyService)
+		default:
+			return fmt.Errorf("unexpected ConsolePlugin type %T", cp)
+		}
 	})
 
 	if err != nil {
@@ -181,7 +270,8 @@ func (r *ConsolePluginResourceReconciler) reconcileConsolePluginCR(
 	}
 
 	logger.Info("ConsolePlugin CR reconciled successfully",
-		"name", cp.Name,
+		"name", cp.GetName(),
+		"apiVersion", apiVersion,
 		"result", res)
 
 	return nil
@@ -223,7 +313,7 @@ func (r *ConsolePluginResourceReconciler) patchClusterConsole(
 func (r *ConsolePluginResourceReconciler) reconcileConsolePluginDeployment(
 	ctx context.Context,
 	client client.Client,
-	gpuAddon *addonv1alpha1.GPUAddon) error {
+	gpuAddon *addonv1alpha1.GPUAddon) (*appsv1.Deployment, error) {
 
 	logger := log.FromContext(ctx, "Reconcile Step", "ConsolePlugin Deployment")
 	existingDP := &appsv1.Deployment{}
@@ -234,7 +324,7 @@ func (r *ConsolePluginResourceReconciler) reconcileConsolePluginDeployment(
 
 	exists := !k8serrors.IsNotFound(err)
 	if err != nil && !k8serrors.IsNotFound(err) {
-		return err
+		return nil, err
 	}
 
 	dp := &appsv1.Deployment{
@@ -253,7 +343,7 @@ func (r *ConsolePluginResourceReconciler) reconcileConsolePluginDeployment(
 	})
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	logger.Info("ConsolePlugin Deployment reconciled successfully",
@@ -261,6 +351,36 @@ func (r *ConsolePluginResourceReconciler) reconcileConsolePluginDeployment(
 		"namespace", dp.Namespace,
 		"result", res)
 
+	return dp, nil
+}
+
+func (r *ConsolePluginResourceReconciler) reconcileConsolePluginPodDisruptionBudget(
+	ctx context.Context,
+	client client.Client,
+	gpuAddon *addonv1alpha1.GPUAddon) error {
+
+	logger := log.FromContext(ctx, "Reconcile Step", "ConsolePlugin PodDisruptionBudget")
+
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      consolePluginName,
+			Namespace: gpuAddon.Namespace,
+		},
+	}
+
+	res, err := controllerutil.CreateOrPatch(ctx, client, pdb, func() error {
+		return r.setDesiredConsolePluginPodDisruptionBudget(client, pdb, gpuAddon)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	logger.Info("ConsolePlugin PodDisruptionBudget reconciled successfully",
+		"name", pdb.Name,
+		"namespace", pdb.Namespace,
+		"result", res)
+
 	return nil
 }
 
@@ -317,14 +437,18 @@ func (r *ConsolePluginResourceReconciler) setDesiredConsolePluginDeployment(
 		return errors.New("deployment cannot be nil")
 	}
 
-	labels := map[string]string{
-		"app": "console-plugin-nvidia-gpu",
-	}
+	labels := getConsolePluginLabels()
 
 	dp.ObjectMeta.Labels = labels
 
+	replicas := addonv1alpha1.GPUAddonDefaultConsolePluginReplicas
+	if gpuAddon.Spec.ConsolePluginReplicas != nil {
+		replicas = *gpuAddon.Spec.ConsolePluginReplicas
+	}
+
 	twentyFivePercent := intstr.FromString("25%")
 	dp.Spec = appsv1.DeploymentSpec{
+		Replicas: &replicas,
 		Strategy: appsv1.DeploymentStrategy{
 			Type: appsv1.RollingUpdateDeploymentStrategyType,
 			RollingUpdate: &appsv1.RollingUpdateDeployment{
@@ -375,7 +499,7 @@ func (r *ConsolePluginResourceReconciler) setDesiredConsolePluginDeployment(
 	consolePluginContainer.ImagePullPolicy = corev1.PullAlways
 	consolePluginContainer.Ports = []corev1.ContainerPort{
 		{
-			ContainerPort: 9443,
+			ContainerPort: consolePluginPort,
 			Protocol:      corev1.ProtocolTCP,
 		},
 	}
@@ -389,10 +513,41 @@ func (r *ConsolePluginResourceReconciler) setDesiredConsolePluginDeployment(
 			corev1.ResourceMemory: resource.MustParse("200Mi"),
 		},
 	}
+	if gpuAddon.Spec.ConsolePluginResources != nil {
+		consolePluginContainer.Resources = *gpuAddon.Spec.ConsolePluginResources.DeepCopy()
+	}
+
+	// The plugin assets are served over HTTPS by nginx, the manifest being
+	// the first thing the console fetches from the plugin.
+	probeHandler := corev1.ProbeHandler{
+		HTTPGet: &corev1.HTTPGetAction{
+			Path:   "/plugin-manifest.json",
+			Port:   intstr.FromInt(consolePluginPort),
+			Scheme: corev1.URISchemeHTTPS,
+		},
+	}
+	consolePluginContainer.ReadinessProbe = &corev1.Probe{
+		ProbeHandler:        probeHandler,
+		InitialDelaySeconds: 5,
+		PeriodSeconds:       10,
+		FailureThreshold:    3,
+	}
+	consolePluginContainer.LivenessProbe = &corev1.Probe{
+		ProbeHandler:        probeHandler,
+		InitialDelaySeconds: 15,
+		PeriodSeconds:       20,
+		FailureThreshold:    3,
+	}
 
 	consolePluginContainer.SecurityContext = &corev1.SecurityContext{
-		AllowPrivilegeEscalation: pointer.Bool(true),
+		AllowPrivilegeEscalation: pointer.Bool(false),
 		RunAsNonRoot:             pointer.Bool(true),
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
 	}
 	consolePluginContainer.VolumeMounts = []corev1.VolumeMount{
 		{
@@ -410,32 +565,200 @@ func (r *ConsolePluginResourceReconciler) setDesiredConsolePluginDeployment(
 		Volumes:       volumes,
 		RestartPolicy: corev1.RestartPolicyAlways,
 		DNSPolicy:     corev1.DNSClusterFirst,
+		SecurityContext: &corev1.PodSecurityContext{
+			RunAsNonRoot: pointer.Bool(true),
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
+		},
+		TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+			{
+				MaxSkew: 1,
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: labels,
+				},
+				WhenUnsatisfiable: corev1.ScheduleAnyway,
+				TopologyKey:       "kubernetes.io/hostname",
+			},
+		},
 	}
 
 	return ctrl.SetControllerReference(gpuAddon, dp, client.Scheme())
 }
 
+func (r *ConsolePluginResourceReconciler) setDesiredConsolePluginPodDisruptionBudget(
+	client client.Client,
+	pdb *policyv1.PodDisruptionBudget,
+	gpuAddon *addonv1alpha1.GPUAddon) error {
+
+	if pdb == nil {
+		return errors.New("poddisruptionbudget cannot be nil")
+	}
+
+	// Allowing a single unavailable pod keeps the plugin served during node
+	// drains when scaled out, without blocking drains when running a single replica.
+	maxUnavailable := intstr.FromInt(1)
+
+	pdb.Spec = policyv1.PodDisruptionBudgetSpec{
+		MaxUnavailable: &maxUnavailable,
+		Selector: &metav1.LabelSelector{
+			MatchLabels: getConsolePluginLabels(),
+		},
+	}
+
+	return ctrl.SetControllerReference(gpuAddon, pdb, client.Scheme())
+}
+
 func (r *ConsolePluginResourceReconciler) setDesiredConsolePlugin(
 	cp *consolev1alpha1.ConsolePlugin,
-	gpuAddon *addonv1alpha1.GPUAddon) error {
+	gpuAddon *addonv1alpha1.GPUAddon,
+	proxyService consolePluginProxyService) error {
 
 	if cp == nil {
 		return errors.New("consoleplugin cannot be nil")
 	}
 
 	cp.Spec = consolev1alpha1.ConsolePluginSpec{
-		DisplayName: "Console Plugin NVIDIA GPU Template",
+		DisplayName: consolePluginDisplayName,
 		Service: consolev1alpha1.ConsolePluginService{
 			Name:      consolePluginName,
 			Namespace: gpuAddon.Namespace,
-			Port:      9443,
+			Port:      consolePluginPort,
 			BasePath:  "/",
 		},
 	}
 
+	if gpuAddon.Spec.ConsolePluginPrometheusProxyEnabled {
+		cp.Spec.Proxy = []consolev1alpha1.ConsolePluginProxy{
+			{
+				Type:  consolev1alpha1.ProxyTypeService,
+				Alias: consolePluginPrometheusProxyAlias,
+				Service: consolev1alpha1.ConsolePluginProxyServiceConfig{
+					Name:      proxyService.name,
+					Namespace: proxyService.namespace,
+					Port:      proxyService.port,
+				},
+				Authorize: true,
+			},
+		}
+	}
+
 	return nil
 }
 
+// setDesiredConsolePluginV1 renders the console.openshift.io/v1 shape of the
+// ConsolePlugin, where the plugin Service moved under spec.backend and i18n
+// loading became part of the spec.
+func (r *ConsolePluginResourceReconciler) setDesiredConsolePluginV1(
+	cp *unstructured.Unstructured,
+	gpuAddon *addonv1alpha1.GPUAddon,
+	proxyService consolePluginProxyService) error {
+
+	if cp == nil {
+		return errors.New("consoleplugin cannot be nil")
+	}
+
+	loadType, _, err := unstructured.NestedString(cp.Object, "spec", "i18n", "loadType")
+	if err != nil {
+		return err
+	}
+
+	// Objects created through v1alpha1 carry the i18n setting as an
+	// annotation, carry it over before dropping the annotation.
+	annotations := cp.GetAnnotations()
+	if legacy, ok := annotations[consolePluginLegacyI18nAnnotation]; ok {
+		loadType = "Lazy"
+		if legacy == "true" {
+			loadType = "Preload"
+		}
+
+		delete(annotations, consolePluginLegacyI18nAnnotation)
+		cp.SetAnnotations(annotations)
+	}
+
+	if loadType == "" {
+		loadType = "Lazy"
+	}
+
+	spec := map[string]interface{}{
+		"displayName": consolePluginDisplayName,
+		"backend": map[string]interface{}{
+			"type": "Service",
+			"service": map[string]interface{}{
+				"name":      consolePluginName,
+				"namespace": gpuAddon.Namespace,
+				"port":      int64(consolePluginPort),
+				"basePath":  "/",
+			},
+		},
+		"i18n": map[string]interface{}{
+			"loadType": loadType,
+		},
+	}
+
+	if gpuAddon.Spec.ConsolePluginPrometheusProxyEnabled {
+		spec["proxy"] = []interface{}{
+			map[string]interface{}{
+				"alias":         consolePluginPrometheusProxyAlias,
+				"authorization": "UserToken",
+				"endpoint": map[string]interface{}{
+					"type": "Service",
+					"service": map[string]interface{}{
+						"name":      proxyService.name,
+						"namespace": proxyService.namespace,
+						"port":      int64(proxyService.port),
+					},
+				},
+			},
+		}
+	}
+
+	return unstructured.SetNestedMap(cp.Object, spec, "spec")
+}
+
+// getConsolePluginProxyService returns the Prometheus serving the addon
+// metrics to the plugin: the addon Prometheus, or the Thanos Querier of the
+// OpenShift monitoring when the Monitoring CR is in UserWorkload mode. The
+// plugin then passes the addon namespace as namespace parameter of its
+// queries, as required by the tenancy port.
+func getConsolePluginProxyService(
+	ctx context.Context,
+	c client.Client,
+	gpuAddon *addonv1alpha1.GPUAddon) (consolePluginProxyService, error) {
+
+	service := consolePluginProxyService{
+		name:      addonv1alpha1.MonitoringPrometheusServiceName,
+		namespace: gpuAddon.Namespace,
+		port:      addonv1alpha1.MonitoringPrometheusServicePort,
+	}
+
+	if !gpuAddon.Spec.ConsolePluginPrometheusProxyEnabled {
+		return service, nil
+	}
+
+	m := &addonv1alpha1.Monitoring{}
+	err := c.Get(ctx, types.NamespacedName{
+		Name:      common.GlobalConfig.AddonID,
+		Namespace: gpuAddon.Namespace,
+	}, m)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return service, nil
+		}
+		return service, fmt.Errorf("unable to get Monitoring %s in %s: %w", common.GlobalConfig.AddonID, gpuAddon.Namespace, err)
+	}
+
+	if m.Spec.Mode == addonv1alpha1.MonitoringModeUserWorkload {
+		service = consolePluginProxyService{
+			name:      addonv1alpha1.MonitoringThanosQuerierServiceName,
+			namespace: addonv1alpha1.MonitoringThanosQuerierServiceNamespace,
+			port:      addonv1alpha1.MonitoringThanosQuerierTenancyPort,
+		}
+	}
+
+	return service, nil
+}
+
 func (r *ConsolePluginResourceReconciler) setDesiredConsolePluginService(
 	client client.Client,
 	s *corev1.Service,
@@ -454,31 +777,33 @@ func (r *ConsolePluginResourceReconciler) setDesiredConsolePluginService(
 			corev1.ServicePort{
 				Name:       "9443-tcp",
 				Protocol:   corev1.ProtocolTCP,
-				Port:       9443,
-				TargetPort: intstr.FromInt(9443),
+				Port:       consolePluginPort,
+				TargetPort: intstr.FromInt(consolePluginPort),
 			},
 		},
-		Selector: map[string]string{
-			"app": "console-plugin-nvidia-gpu",
-		},
-		Type: corev1.ServiceTypeClusterIP,
+		Selector: getConsolePluginLabels(),
+		Type:     corev1.ServiceTypeClusterIP,
 	}
 
 	return ctrl.SetControllerReference(gpuAddon, s, client.Scheme())
 }
 
 func (r *ConsolePluginResourceReconciler) deleteConsolePluginCR(ctx context.Context, c client.Client) (bool, error) {
-	cp := &consolev1alpha1.ConsolePlugin{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: consolePluginName,
-		},
+	apiVersion, err := getConsolePluginAPIVersion(c)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return true, nil
+		}
+		return false, err
 	}
 
+	cp := newConsolePlugin(apiVersion)
+
 	if err := c.Delete(ctx, cp); err != nil {
 		if k8serrors.IsNotFound(err) {
 			return true, nil
 		}
-		return false, fmt.Errorf("failed to delete ConsolePlugin CR %s: %w", cp.Name, err)
+		return false, fmt.Errorf("failed to delete ConsolePlugin CR %s: %w", cp.GetName(), err)
 	}
 
 	return false, nil
@@ -502,6 +827,24 @@ func (r *ConsolePluginResourceReconciler) deleteConsolePluginDeployment(ctx cont
 	return false, nil
 }
 
+func (r *ConsolePluginResourceReconciler) deleteConsolePluginPodDisruptionBudget(ctx context.Context, c client.Client) (bool, error) {
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: common.GlobalConfig.AddonNamespace,
+			Name:      consolePluginName,
+		},
+	}
+
+	if err := c.Delete(ctx, pdb); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to delete ConsolePlugin PodDisruptionBudget %s: %w", pdb.Name, err)
+	}
+
+	return false, nil
+}
+
 func (r *ConsolePluginResourceReconciler) deleteConsolePluginService(ctx context.Context, c client.Client) (bool, error) {
 	s := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -536,10 +879,74 @@ func (r *ConsolePluginResourceReconciler) getDeployedConditionSuccess() metav1.C
 		"ConsolePlugin deployed successfully")
 }
 
-func (r *ConsolePluginResourceReconciler) getDeployedConditionNotSupported() metav1.Condition {
+func (r *ConsolePluginResourceReconciler) getDeployedConditionUnavailable(dp *appsv1.Deployment) metav1.Condition {
+	desired := int32(1)
+	if dp.Spec.Replicas != nil {
+		desired = *dp.Spec.Replicas
+	}
+
+	return common.NewCondition(
+		ConsolePluginDeployedCondition,
+		metav1.ConditionFalse,
+		"DeploymentUnavailable",
+		fmt.Sprintf("ConsolePlugin Deployment has %d/%d available replicas", dp.Status.AvailableReplicas, desired))
+}
+
+func (r *ConsolePluginResourceReconciler) getDeployedConditionNotSupported(message string) metav1.Condition {
 	return common.NewCondition(
 		ConsolePluginDeployedCondition,
 		metav1.ConditionTrue,
 		"NotSupported",
-		"ConsolePlugin is not supported when OpenShift version <4.10")
+		message)
+}
+
+func getConsolePluginLabels() map[string]string {
+	return map[string]string{
+		"app": consolePluginName,
+	}
+}
+
+// isDeploymentAvailable reports whether the Deployment has reached its
+// minimum availability.
+func isDeploymentAvailable(dp *appsv1.Deployment) bool {
+	for _, condition := range dp.Status.Conditions {
+		if condition.Type == appsv1.DeploymentAvailable {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// getConsolePluginAPIVersion discovers which ConsolePlugin API version is
+// served by the cluster, preferring console.openshift.io/v1 over v1alpha1.
+// A NoMatch error is returned when neither is served.
+func getConsolePluginAPIVersion(c client.Client) (string, error) {
+	mapping, err := c.RESTMapper().RESTMapping(
+		consolePluginGroupKind,
+		consolePluginV1.Version,
+		consolev1alpha1.GroupVersion.Version)
+	if err != nil {
+		return "", err
+	}
+
+	return mapping.GroupVersionKind.Version, nil
+}
+
+// newConsolePlugin returns an empty ConsolePlugin for the given API version.
+// The v1 API is not part of the vendored OpenShift API, so it is handled as
+// unstructured content.
+func newConsolePlugin(apiVersion string) client.Object {
+	if apiVersion == consolev1alpha1.GroupVersion.Version {
+		return &consolev1alpha1.ConsolePlugin{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: consolePluginName,
+			},
+		}
+	}
+
+	cp := &unstructured.Unstructured{}
+	cp.SetGroupVersionKind(consolePluginV1)
+	cp.SetName(consolePluginName)
+
+	return cp
 }
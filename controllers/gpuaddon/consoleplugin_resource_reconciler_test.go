@@ -25,11 +25,15 @@ import (
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned/scheme"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -74,6 +78,7 @@ var _ = Describe("ConsolePlugin Resource Reconcile", Ordered, func() {
 		Expect(appsv1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
 		Expect(corev1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
 		Expect(configv1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		Expect(policyv1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
 
 		var cp consolev1alpha1.ConsolePlugin
 		var dp appsv1.Deployment
@@ -137,6 +142,7 @@ var _ = Describe("ConsolePlugin Resource Reconcile", Ordered, func() {
 			c := fake.
 				NewClientBuilder().
 				WithScheme(scheme).
+				WithRESTMapper(newConsolePluginRESTMapper("v1alpha1")).
 				WithRuntimeObjects(clusterVersion, console).
 				Build()
 
@@ -160,9 +166,19 @@ var _ = Describe("ConsolePlugin Resource Reconcile", Ordered, func() {
 					Name: "console-plugin-nvidia-gpu",
 				}, &cp)
 				Expect(err).ShouldNot(HaveOccurred())
+				Expect(cp.Spec.Proxy).To(BeEmpty())
+
+				pdb := &policyv1.PodDisruptionBudget{}
+				err = c.Get(context.TODO(), types.NamespacedName{
+					Namespace: gpuAddon.Namespace,
+					Name:      "console-plugin-nvidia-gpu",
+				}, pdb)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(pdb.Spec.MaxUnavailable.IntValue()).To(Equal(1))
 
 				Expect(conditions).To(HaveLen(1))
-				Expect(conditions[0].Reason).To(Equal("Success"))
+				Expect(conditions[0].Status).To(Equal(metav1.ConditionFalse))
+				Expect(conditions[0].Reason).To(Equal("DeploymentUnavailable"))
 
 				err = c.Get(context.TODO(), client.ObjectKey{
 					Name: "cluster",
@@ -173,6 +189,34 @@ var _ = Describe("ConsolePlugin Resource Reconcile", Ordered, func() {
 				Expect(console.Spec.Plugins[0]).To(Equal("console-plugin-nvidia-gpu"))
 			})
 
+			It("should harden the ConsolePlugin Deployment", func() {
+				Expect(*dp.Spec.Replicas).To(Equal(int32(2)))
+				Expect(dp.Spec.Template.Spec.TopologySpreadConstraints).To(HaveLen(1))
+				Expect(dp.Spec.Template.Spec.SecurityContext.SeccompProfile.Type).To(Equal(corev1.SeccompProfileTypeRuntimeDefault))
+
+				container := dp.Spec.Template.Spec.Containers[0]
+				Expect(*container.SecurityContext.AllowPrivilegeEscalation).To(BeFalse())
+				Expect(container.SecurityContext.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")))
+				Expect(container.ReadinessProbe).ToNot(BeNil())
+				Expect(container.LivenessProbe).ToNot(BeNil())
+			})
+
+			It("should report success once the Deployment is available", func() {
+				dp.Status.AvailableReplicas = 2
+				dp.Status.Conditions = []appsv1.DeploymentCondition{
+					{
+						Type:   appsv1.DeploymentAvailable,
+						Status: corev1.ConditionTrue,
+					},
+				}
+				Expect(c.Status().Update(context.TODO(), &dp)).To(Succeed())
+
+				conditions, err := rrec.Reconcile(context.TODO(), c, &gpuAddon)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(conditions).To(HaveLen(1))
+				Expect(conditions[0].Reason).To(Equal("Success"))
+			})
+
 			Context("and reconciled more than once", func() {
 				It("should not add an item in Console `cluster` plugins array", func() {
 					_, err := rrec.Reconcile(context.TODO(), c, &gpuAddon)
@@ -192,6 +236,260 @@ var _ = Describe("ConsolePlugin Resource Reconcile", Ordered, func() {
 			})
 		})
 
+		Context("when console.openshift.io/v1 is served", func() {
+			gpuAddon := gpuAddon.DeepCopy()
+			gpuAddon.Spec = addonv1alpha1.GPUAddonSpec{
+				ConsolePluginEnabled: true,
+			}
+
+			It("should create a v1 ConsolePlugin with a Service backend", func() {
+				c := fake.
+					NewClientBuilder().
+					WithScheme(scheme).
+					WithRESTMapper(newConsolePluginRESTMapper("v1", "v1alpha1")).
+					WithRuntimeObjects(clusterVersion, console.DeepCopy()).
+					Build()
+
+				conditions, err := rrec.Reconcile(context.TODO(), c, gpuAddon)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(conditions).To(HaveLen(1))
+				Expect(conditions[0].Reason).To(Equal("DeploymentUnavailable"))
+
+				cp := newTestConsolePluginV1()
+				err = c.Get(context.TODO(), client.ObjectKey{
+					Name: "console-plugin-nvidia-gpu",
+				}, cp)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				backendType, _, _ := unstructured.NestedString(cp.Object, "spec", "backend", "type")
+				Expect(backendType).To(Equal("Service"))
+				serviceName, _, _ := unstructured.NestedString(cp.Object, "spec", "backend", "service", "name")
+				Expect(serviceName).To(Equal("console-plugin-nvidia-gpu"))
+				loadType, _, _ := unstructured.NestedString(cp.Object, "spec", "i18n", "loadType")
+				Expect(loadType).To(Equal("Lazy"))
+
+				err = c.Get(context.TODO(), client.ObjectKey{
+					Name: "console-plugin-nvidia-gpu",
+				}, &consolev1alpha1.ConsolePlugin{})
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			})
+
+			It("should migrate the legacy v1alpha1 i18n annotation", func() {
+				existing := newTestConsolePluginV1()
+				existing.SetAnnotations(map[string]string{
+					"console.openshift.io/use-i18n": "true",
+				})
+				Expect(unstructured.SetNestedField(existing.Object, "/", "spec", "service", "basePath")).To(Succeed())
+
+				c := fake.
+					NewClientBuilder().
+					WithScheme(scheme).
+					WithRESTMapper(newConsolePluginRESTMapper("v1", "v1alpha1")).
+					WithRuntimeObjects(clusterVersion, console.DeepCopy(), existing).
+					Build()
+
+				_, err := rrec.Reconcile(context.TODO(), c, gpuAddon)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				cp := newTestConsolePluginV1()
+				err = c.Get(context.TODO(), client.ObjectKey{
+					Name: "console-plugin-nvidia-gpu",
+				}, cp)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(cp.GetAnnotations()).ShouldNot(HaveKey("console.openshift.io/use-i18n"))
+				loadType, _, _ := unstructured.NestedString(cp.Object, "spec", "i18n", "loadType")
+				Expect(loadType).To(Equal("Preload"))
+				_, found, _ := unstructured.NestedMap(cp.Object, "spec", "service")
+				Expect(found).To(BeFalse())
+
+				_, err = rrec.Reconcile(context.TODO(), c, gpuAddon)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				err = c.Get(context.TODO(), client.ObjectKey{
+					Name: "console-plugin-nvidia-gpu",
+				}, cp)
+				Expect(err).ShouldNot(HaveOccurred())
+				loadType, _, _ = unstructured.NestedString(cp.Object, "spec", "i18n", "loadType")
+				Expect(loadType).To(Equal("Preload"))
+			})
+
+			It("should delete the v1 ConsolePlugin", func() {
+				c := fake.
+					NewClientBuilder().
+					WithScheme(scheme).
+					WithRESTMapper(newConsolePluginRESTMapper("v1", "v1alpha1")).
+					WithRuntimeObjects(newTestConsolePluginV1()).
+					Build()
+
+				deleted, err := rrec.Delete(context.TODO(), c)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(deleted).To(BeFalse())
+
+				err = c.Get(context.TODO(), client.ObjectKey{
+					Name: "console-plugin-nvidia-gpu",
+				}, newTestConsolePluginV1())
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+
+		Context("when the Prometheus proxy is enabled", func() {
+			gpuAddon := gpuAddon.DeepCopy()
+			gpuAddon.Spec = addonv1alpha1.GPUAddonSpec{
+				ConsolePluginEnabled:                true,
+				ConsolePluginPrometheusProxyEnabled: true,
+			}
+
+			It("should proxy the v1alpha1 ConsolePlugin to the addon Prometheus", func() {
+				c := fake.
+					NewClientBuilder().
+					WithScheme(scheme).
+					WithRESTMapper(newConsolePluginRESTMapper("v1alpha1")).
+					WithRuntimeObjects(clusterVersion, console.DeepCopy()).
+					Build()
+
+				_, err := rrec.Reconcile(context.TODO(), c, gpuAddon)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				cp := &consolev1alpha1.ConsolePlugin{}
+				err = c.Get(context.TODO(), client.ObjectKey{
+					Name: "console-plugin-nvidia-gpu",
+				}, cp)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(cp.Spec.Proxy).To(HaveLen(1))
+				Expect(cp.Spec.Proxy[0].Alias).To(Equal("prometheus"))
+				Expect(cp.Spec.Proxy[0].Authorize).To(BeTrue())
+				Expect(cp.Spec.Proxy[0].Service.Name).To(Equal("gpuaddon-prometheus-service"))
+				Expect(cp.Spec.Proxy[0].Service.Namespace).To(Equal(gpuAddon.Namespace))
+				Expect(cp.Spec.Proxy[0].Service.Port).To(Equal(int32(9339)))
+			})
+
+			It("should proxy the v1 ConsolePlugin to the addon Prometheus", func() {
+				c := fake.
+					NewClientBuilder().
+					WithScheme(scheme).
+					WithRESTMapper(newConsolePluginRESTMapper("v1", "v1alpha1")).
+					WithRuntimeObjects(clusterVersion, console.DeepCopy()).
+					Build()
+
+				_, err := rrec.Reconcile(context.TODO(), c, gpuAddon)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				cp := newTestConsolePluginV1()
+				err = c.Get(context.TODO(), client.ObjectKey{
+					Name: "console-plugin-nvidia-gpu",
+				}, cp)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				proxies, _, _ := unstructured.NestedSlice(cp.Object, "spec", "proxy")
+				Expect(proxies).To(HaveLen(1))
+
+				proxy := proxies[0].(map[string]interface{})
+				Expect(proxy["alias"]).To(Equal("prometheus"))
+				Expect(proxy["authorization"]).To(Equal("UserToken"))
+				serviceName, _, _ := unstructured.NestedString(proxy, "endpoint", "service", "name")
+				Expect(serviceName).To(Equal("gpuaddon-prometheus-service"))
+				port, _, _ := unstructured.NestedInt64(proxy, "endpoint", "service", "port")
+				Expect(port).To(Equal(int64(9339)))
+			})
+		})
+
+		Context("when the Prometheus proxy is enabled in UserWorkload mode", func() {
+			gpuAddon := gpuAddon.DeepCopy()
+			gpuAddon.Spec = addonv1alpha1.GPUAddonSpec{
+				ConsolePluginEnabled:                true,
+				ConsolePluginPrometheusProxyEnabled: true,
+			}
+
+			monitoring := &addonv1alpha1.Monitoring{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      common.GlobalConfig.AddonID,
+					Namespace: gpuAddon.Namespace,
+				},
+				Spec: addonv1alpha1.MonitoringSpec{
+					Mode: addonv1alpha1.MonitoringModeUserWorkload,
+				},
+			}
+
+			It("should proxy the v1alpha1 ConsolePlugin to the Thanos Querier", func() {
+				c := fake.
+					NewClientBuilder().
+					WithScheme(scheme).
+					WithRESTMapper(newConsolePluginRESTMapper("v1alpha1")).
+					WithRuntimeObjects(clusterVersion, console.DeepCopy(), monitoring.DeepCopy()).
+					Build()
+
+				_, err := rrec.Reconcile(context.TODO(), c, gpuAddon)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				cp := &consolev1alpha1.ConsolePlugin{}
+				err = c.Get(context.TODO(), client.ObjectKey{
+					Name: "console-plugin-nvidia-gpu",
+				}, cp)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(cp.Spec.Proxy).To(HaveLen(1))
+				Expect(cp.Spec.Proxy[0].Service.Name).To(Equal("thanos-querier"))
+				Expect(cp.Spec.Proxy[0].Service.Namespace).To(Equal("openshift-monitoring"))
+				Expect(cp.Spec.Proxy[0].Service.Port).To(Equal(int32(9092)))
+			})
+
+			It("should proxy the v1 ConsolePlugin to the Thanos Querier", func() {
+				c := fake.
+					NewClientBuilder().
+					WithScheme(scheme).
+					WithRESTMapper(newConsolePluginRESTMapper("v1", "v1alpha1")).
+					WithRuntimeObjects(clusterVersion, console.DeepCopy(), monitoring.DeepCopy()).
+					Build()
+
+				_, err := rrec.Reconcile(context.TODO(), c, gpuAddon)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				cp := newTestConsolePluginV1()
+				err = c.Get(context.TODO(), client.ObjectKey{
+					Name: "console-plugin-nvidia-gpu",
+				}, cp)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				proxies, _, _ := unstructured.NestedSlice(cp.Object, "spec", "proxy")
+				Expect(proxies).To(HaveLen(1))
+
+				service, _, _ := unstructured.NestedMap(proxies[0].(map[string]interface{}), "endpoint", "service")
+				Expect(service).To(Equal(map[string]interface{}{
+					"name":      "thanos-querier",
+					"namespace": "openshift-monitoring",
+					"port":      int64(9092),
+				}))
+			})
+		})
+
+		Context("when no ConsolePlugin API is served", func() {
+			It("should not reconcile the ConsolePlugin components", func() {
+				gpuAddon := gpuAddon.DeepCopy()
+				gpuAddon.Spec = addonv1alpha1.GPUAddonSpec{
+					ConsolePluginEnabled: true,
+				}
+
+				c := fake.
+					NewClientBuilder().
+					WithScheme(scheme).
+					WithRuntimeObjects(clusterVersion, console.DeepCopy()).
+					Build()
+
+				conditions, err := rrec.Reconcile(context.TODO(), c, gpuAddon)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(conditions).To(HaveLen(1))
+				Expect(conditions[0].Reason).To(Equal("NotSupported"))
+
+				err = c.Get(context.TODO(), types.NamespacedName{
+					Namespace: gpuAddon.Namespace,
+					Name:      "console-plugin-nvidia-gpu",
+				}, &dp)
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+
 		Context("when disabled", func() {
 			It("should not create the ConsolePlugin components", func() {
 				gpuAddon.Spec = addonv1alpha1.GPUAddonSpec{
@@ -257,6 +555,7 @@ var _ = Describe("ConsolePlugin Resource Reconcile", Ordered, func() {
 				c := fake.
 					NewClientBuilder().
 					WithScheme(scheme).
+					WithRESTMapper(newConsolePluginRESTMapper("v1alpha1")).
 					WithRuntimeObjects(cp, dp, s, clusterVersion).
 					Build()
 
@@ -289,6 +588,28 @@ var _ = Describe("ConsolePlugin Resource Reconcile", Ordered, func() {
 		})
 	})
 
+	Context("Watch", func() {
+		common.ProcessConfig()
+
+		It("should reconcile the GPUAddon CR when the addon ConsolePlugin changes", func() {
+			Expect(getGPUAddonRequestsForConsolePlugin(newConsolePlugin("v1"))).To(ConsistOf(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      common.GlobalConfig.AddonID,
+					Namespace: common.GlobalConfig.AddonNamespace,
+				},
+			}))
+		})
+
+		It("should ignore the other ConsolePlugins", func() {
+			cp := &consolev1alpha1.ConsolePlugin{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "other-plugin",
+				},
+			}
+			Expect(getGPUAddonRequestsForConsolePlugin(cp)).To(BeEmpty())
+		})
+	})
+
 	Context("Delete", func() {
 		common.ProcessConfig()
 		rrec := &ConsolePluginResourceReconciler{}
@@ -313,11 +634,13 @@ var _ = Describe("ConsolePlugin Resource Reconcile", Ordered, func() {
 
 		scheme := scheme.Scheme
 		Expect(consolev1alpha1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		Expect(policyv1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
 
 		It("should delete the ConsolePlugin components", func() {
 			c := fake.
 				NewClientBuilder().
 				WithScheme(scheme).
+				WithRESTMapper(newConsolePluginRESTMapper("v1alpha1")).
 				WithRuntimeObjects(cp, dp, s).
 				Build()
 
@@ -351,3 +674,18 @@ var _ = Describe("ConsolePlugin Resource Reconcile", Ordered, func() {
 		})
 	})
 })
+
+func newConsolePluginRESTMapper(versions ...string) meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, v := range versions {
+		mapper.Add(consolePluginGroupKind.WithVersion(v), meta.RESTScopeRoot)
+	}
+	return mapper
+}
+
+func newTestConsolePluginV1() *unstructured.Unstructured {
+	cp := &unstructured.Unstructured{}
+	cp.SetGroupVersionKind(consolePluginGroupKind.WithVersion("v1"))
+	cp.SetName("console-plugin-nvidia-gpu")
+	return cp
+}
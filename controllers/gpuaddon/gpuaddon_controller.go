@@ -20,13 +20,15 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
-	consolev1alpha1 "github.com/openshift/api/console/v1alpha1"
 	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -34,16 +36,24 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
 	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/health"
 )
 
 // GPUAddonReconciler reconciles a GPUAddon object
 type GPUAddonReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// ReconcileTracker reports the outcome of the reconciliations in the
+	// readiness checks and metrics. Not tracked when not set.
+	ReconcileTracker *health.ReconcileTracker
 }
 
 // List of other resources managed by this operator.
@@ -59,13 +69,14 @@ var resourceOrderedReconcilers = []ResourceReconciler{
 //+kubebuilder:rbac:groups=nvidia.addons.rh-ecosystem-edge.io,namespace=system,resources=gpuaddons/finalizers,verbs=update
 //+kubebuilder:rbac:groups=nvidia.com,resources=clusterpolicies,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=nfd.openshift.io,namespace=system,resources=nodefeaturediscoveries,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=operators.coreos.com,namespace=system,resources=clusterserviceversions,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=operators.coreos.com,namespace=system,resources=clusterserviceversions,verbs=get;list;watch;patch;delete
 //+kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions,verbs=get;list;watch
 //+kubebuilder:rbac:groups=operators.coreos.com,namespace=system,resources=subscriptions,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=console.openshift.io,resources=consoleplugins,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=operator.openshift.io,resources=consoles,verbs=get;list;watch;patch
 //+kubebuilder:rbac:groups=apps,namespace=system,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",namespace=system,resources=services,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=policy,namespace=system,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -91,12 +102,16 @@ func (r *GPUAddonReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 	if !gpuAddon.ObjectMeta.DeletionTimestamp.IsZero() {
 		logger.Info(fmt.Sprintf("GPUAddon CR %v/%v marked for deletion", req.Namespace, req.Name))
+		AddonUninstalling.WithLabelValues().Set(1)
 		if controllerutil.ContainsFinalizer(&gpuAddon, common.GlobalConfig.AddonID) {
 
-			err := r.removeOwnedResources(ctx)
+			done, requeue, err := r.uninstall(ctx, &gpuAddon)
 			if err != nil {
 				return ctrl.Result{}, err
 			}
+			if !done {
+				return ctrl.Result{RequeueAfter: requeue}, nil
+			}
 
 			controllerutil.RemoveFinalizer(&gpuAddon, common.GlobalConfig.AddonID)
 
@@ -106,6 +121,8 @@ func (r *GPUAddonReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		}
 		return ctrl.Result{}, nil
 	}
+	AddonUninstalling.WithLabelValues().Set(0)
+
 	addonConditions := []metav1.Condition{}
 
 	if err := r.registerFinilizerIfNeeded(ctx, &gpuAddon); err != nil {
@@ -113,7 +130,9 @@ func (r *GPUAddonReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	}
 
 	for _, rr := range resourceOrderedReconcilers {
+		start := time.Now()
 		conditions, err := rr.Reconcile(ctx, r.Client, &gpuAddon)
+		observeResourceReconcile(getResourceName(rr), start, conditions, err)
 		addonConditions = append(addonConditions, conditions...)
 		if err != nil {
 			logger.Error(err, "Reconcilation failed", "resource", gpuAddon.Name, "namespace", gpuAddon.Namespace)
@@ -126,19 +145,73 @@ func (r *GPUAddonReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *GPUAddonReconciler) SetupWithManager(mgr ctrl.Manager) (controller.Controller, error) {
-	return ctrl.NewControllerManagedBy(mgr).
+	if err := registerGPUNodeCollector(mgr.GetClient()); err != nil {
+		return nil, fmt.Errorf("failed to register the GPU node metrics: %w", err)
+	}
+
+	bldr := ctrl.NewControllerManagedBy(mgr).
 		For(&addonv1alpha1.GPUAddon{}).
 		Owns(&operatorsv1alpha1.Subscription{}).
 		Owns(&nfdv1.NodeFeatureDiscovery{}).
-		Owns(&consolev1alpha1.ConsolePlugin{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
-		Build(r)
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Watches(
+			&source.Kind{Type: &addonv1alpha1.Monitoring{}},
+			handler.EnqueueRequestsFromMapFunc(getGPUAddonRequestsForMonitoring))
+
+	// The ConsolePlugin is cluster-scoped, so it cannot be owned by the
+	// GPUAddon CR, and it is watched in the API version served by the
+	// cluster, if any.
+	apiVersion, err := getConsolePluginAPIVersion(mgr.GetClient())
+	if err != nil && !meta.IsNoMatchError(err) {
+		return nil, fmt.Errorf("failed to look up the ConsolePlugin API version: %w", err)
+	}
+	if err == nil {
+		bldr = bldr.Watches(
+			&source.Kind{Type: newConsolePlugin(apiVersion)},
+			handler.EnqueueRequestsFromMapFunc(getGPUAddonRequestsForConsolePlugin))
+	}
+
+	return bldr.Build(r.ReconcileTracker.Track("gpuaddon", r))
+}
+
+// getGPUAddonRequestsForConsolePlugin reconciles the GPUAddon CR when the
+// ConsolePlugin of the addon changes.
+func getGPUAddonRequestsForConsolePlugin(object client.Object) []reconcile.Request {
+	if object.GetName() != consolePluginName {
+		return nil
+	}
+
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Name:      common.GlobalConfig.AddonID,
+			Namespace: common.GlobalConfig.AddonNamespace,
+		},
+	}}
+}
+
+// getGPUAddonRequestsForMonitoring reconciles the GPUAddon CR when the
+// Monitoring CR changes, the console plugin proxying the Prometheus queries
+// according to its mode.
+func getGPUAddonRequestsForMonitoring(object client.Object) []reconcile.Request {
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Name:      common.GlobalConfig.AddonID,
+			Namespace: object.GetNamespace(),
+		},
+	}}
 }
 
 func (r *GPUAddonReconciler) patchStatus(ctx context.Context, gpuAddon addonv1alpha1.GPUAddon, conditions []metav1.Condition, err error) error {
 	patch := client.MergeFrom(gpuAddon.DeepCopy())
+	// The uninstall policy is enforced, and reported, by the ConfigMap
+	// controller.
+	uninstallBlocked := meta.FindStatusCondition(gpuAddon.Status.Conditions, addonv1alpha1.GPUAddonUninstallBlockedCondition)
 	gpuAddon.Status.Conditions = conditions
+	if uninstallBlocked != nil {
+		gpuAddon.Status.Conditions = append(gpuAddon.Status.Conditions, *uninstallBlocked)
+	}
 	if err != nil {
 		gpuAddon.Status.Phase = addonv1alpha1.GPUAddonPhaseFailed
 	} else {
@@ -161,6 +234,10 @@ func (r *GPUAddonReconciler) patchStatus(ctx context.Context, gpuAddon addonv1al
 	if patchErr != nil {
 		return fmt.Errorf("failed to patch status: %w", patchErr)
 	}
+
+	setConditionMetrics(gpuAddon.Status.Conditions)
+	setPhaseMetric(gpuAddon.Status.Phase)
+
 	return err
 }
 
@@ -178,42 +255,13 @@ func (r *GPUAddonReconciler) registerFinilizerIfNeeded(ctx context.Context, gpuA
 	return nil
 }
 
+// removeOwnedResources requests the deletion of the resources managed by the
+// addon, in the reverse order of their creation.
 func (r *GPUAddonReconciler) removeOwnedResources(ctx context.Context) error {
-	deleted := make([]bool, len(resourceOrderedReconcilers))
-
 	for i := len(resourceOrderedReconcilers) - 1; i >= 0; i-- {
-		removed, err := resourceOrderedReconcilers[i].Delete(ctx, r.Client)
-		if err != nil {
+		if _, err := resourceOrderedReconcilers[i].Delete(ctx, r.Client); err != nil {
 			return err
 		}
-		deleted[i] = removed
-	}
-
-	for i := range deleted {
-		if !deleted[i] {
-			return fmt.Errorf("not all resources have been deleted yet, won't remove add-on CSV")
-		}
-	}
-
-	if err := r.removeSelfCsv(ctx); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func (r *GPUAddonReconciler) removeSelfCsv(ctx context.Context) error {
-	logger := log.FromContext(ctx).WithValues("Reconcile Step", "Addon CSV Deletion")
-	logger.Info("Cleanup Reconcile | Delete own CSV")
-
-	addonCsv, err := common.GetCsvWithPrefix(r.Client, common.GlobalConfig.AddonNamespace, common.GlobalConfig.AddonID)
-	if err != nil {
-		return err
-	}
-
-	err = r.Delete(ctx, addonCsv)
-	if err != nil && !k8serrors.IsNotFound(err) {
-		return fmt.Errorf("failed to delete GPUAddon Operator CSV %s: %w", addonCsv.Name, err)
 	}
 
 	return nil
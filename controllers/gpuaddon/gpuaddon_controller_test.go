@@ -7,12 +7,15 @@ import (
 
 	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
 	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
 	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
 	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned/scheme"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -104,19 +107,17 @@ var _ = Describe("GPUAddon Reconcile", Ordered, func() {
 
 		_, err := r.Reconcile(context.TODO(), req)
 
-		It("should return an error", func() {
-			Expect(err).Should(HaveOccurred())
-			Expect(err.Error()).Should(ContainSubstring("not all resources have been deleted"))
+		It("should not return an error", func() {
+			Expect(err).ShouldNot(HaveOccurred())
 		})
 
-		It("should delete the GPUAddon CSV", func() {
+		It("should leave the GPUAddon CSV to the uninstall of the ConfigMap controller", func() {
 			g := &operatorsv1alpha1.ClusterServiceVersion{}
 			err := r.Client.Get(context.TODO(), types.NamespacedName{
 				Name:      common.GlobalConfig.AddonID,
 				Namespace: common.GlobalConfig.AddonNamespace,
 			}, g)
-			Expect(err).Should(HaveOccurred())
-			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			Expect(err).ShouldNot(HaveOccurred())
 		})
 
 		It("should already find the GPUAddon CR deleted", func() {
@@ -251,6 +252,9 @@ func newTestGPUAddonReconciler(objs ...runtime.Object) *GPUAddonReconciler {
 	Expect(nfdv1.AddToScheme(s)).ShouldNot(HaveOccurred())
 	Expect(configv1.AddToScheme(s)).ShouldNot(HaveOccurred())
 	Expect(appsv1.AddToScheme(s)).ShouldNot(HaveOccurred())
+	Expect(policyv1.AddToScheme(s)).ShouldNot(HaveOccurred())
+	Expect(operatorv1.AddToScheme(s)).ShouldNot(HaveOccurred())
+	Expect(apiextensionsv1.AddToScheme(s)).ShouldNot(HaveOccurred())
 
 	clusterVersion := &configv1.ClusterVersion{
 		ObjectMeta: metav1.ObjectMeta{
@@ -17,8 +17,17 @@ limitations under the License.
 package gpuaddon
 
 import (
+	"reflect"
+	"strings"
+	"time"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
 	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/version"
 )
 
 var (
@@ -29,10 +38,168 @@ var (
 		},
 		[]string{},
 	)
+
+	ClusterPolicyReady = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "nvidia_gpuaddon_gpu_operator_clusterpolicy_ready",
+			Help: "Reports whether the NVIDIA GPU Operator ClusterPolicy is ready",
+		},
+		[]string{},
+	)
+
+	AddonUninstalling = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "nvidia_gpuaddon_uninstalling",
+			Help: "Reports whether the NVIDIA GPUAddon is being uninstalled",
+		},
+		[]string{},
+	)
+
+	ClusterPolicyCRDAvailable = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "nvidia_gpuaddon_gpu_operator_clusterpolicy_crd_available",
+			Help: "Reports whether the NVIDIA GPU Operator ClusterPolicy CRD is available and watched",
+		},
+	)
+
+	ClusterPolicyState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "nvidia_gpuaddon_gpu_operator_clusterpolicy_state",
+			Help: "Reports the state of the NVIDIA GPU Operator ClusterPolicy, 1 for the current state",
+		},
+		[]string{"state"},
+	)
+
+	ResourceReconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "nvidia_gpuaddon_resource_reconcile_duration_seconds",
+			Help:    "Duration of the reconciliation of a resource managed by the NVIDIA GPUAddon",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"resource"},
+	)
+
+	ResourceReconcileErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "nvidia_gpuaddon_resource_reconcile_errors_total",
+			Help: "Number of failed reconciliations of a resource managed by the NVIDIA GPUAddon",
+		},
+		[]string{"resource", "reason"},
+	)
+
+	ResourceReconcileLastSuccess = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "nvidia_gpuaddon_resource_reconcile_last_success_timestamp_seconds",
+			Help: "Time of the last successful reconciliation of a resource managed by the NVIDIA GPUAddon",
+		},
+		[]string{"resource"},
+	)
+
+	AddonCondition = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "nvidia_gpuaddon_condition",
+			Help: "Reports the conditions of the NVIDIA GPUAddon, 1 for the current status of each condition",
+		},
+		[]string{"type", "status"},
+	)
+
+	AddonPhase = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "nvidia_gpuaddon_phase",
+			Help: "Reports the phase of the NVIDIA GPUAddon, 1 for the current phase",
+		},
+		[]string{"phase"},
+	)
+
+	AddonInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "nvidia_gpuaddon_info",
+			Help: "Version of the NVIDIA GPUAddon operator",
+		},
+		[]string{"version"},
+	)
 )
 
 func init() {
 	metrics.Registry.MustRegister(
 		SubscriptionInstalled,
+		ClusterPolicyReady,
+		AddonUninstalling,
+		ClusterPolicyCRDAvailable,
+		ClusterPolicyState,
+		ResourceReconcileDuration,
+		ResourceReconcileErrors,
+		ResourceReconcileLastSuccess,
+		AddonCondition,
+		AddonPhase,
+		AddonInfo,
 	)
+
+	AddonInfo.WithLabelValues(version.Version()).Set(1)
+}
+
+// getResourceName returns the name of the resource handled by a
+// ResourceReconciler, e.g. ClusterPolicy.
+func getResourceName(rr ResourceReconciler) string {
+	name := reflect.TypeOf(rr).Elem().Name()
+	return strings.TrimSuffix(name, "ResourceReconciler")
+}
+
+// observeResourceReconcile records the outcome of the reconciliation of a
+// resource. The reason of a failure is the one of the failed condition.
+func observeResourceReconcile(resource string, start time.Time, conditions []metav1.Condition, err error) {
+	ResourceReconcileDuration.WithLabelValues(resource).Observe(time.Since(start).Seconds())
+
+	if err == nil {
+		ResourceReconcileLastSuccess.WithLabelValues(resource).SetToCurrentTime()
+		return
+	}
+
+	reason := "Unknown"
+	for _, condition := range conditions {
+		if condition.Status == metav1.ConditionFalse {
+			reason = condition.Reason
+			break
+		}
+	}
+	ResourceReconcileErrors.WithLabelValues(resource, reason).Inc()
+}
+
+func setConditionMetrics(conditions []metav1.Condition) {
+	AddonCondition.Reset()
+	for _, condition := range conditions {
+		for _, status := range []metav1.ConditionStatus{
+			metav1.ConditionTrue,
+			metav1.ConditionFalse,
+			metav1.ConditionUnknown,
+		} {
+			AddonCondition.WithLabelValues(condition.Type, string(status)).Set(boolToFloat(condition.Status == status))
+		}
+	}
+}
+
+func setPhaseMetric(phase addonv1alpha1.GPUAddonPhase) {
+	for _, p := range []addonv1alpha1.GPUAddonPhase{
+		addonv1alpha1.GPUAddonPhaseFailed,
+		addonv1alpha1.GPUAddonPhaseIdle,
+		addonv1alpha1.GPUAddonPhaseInstalling,
+		addonv1alpha1.GPUAddonPhaseReady,
+		addonv1alpha1.GPUAddonPhaseUpdating,
+		addonv1alpha1.GPUAddonPhaseUninstalling,
+	} {
+		AddonPhase.WithLabelValues(string(p)).Set(boolToFloat(phase == p))
+	}
+}
+
+func setClusterPolicyStateMetric(state gpuv1.State) {
+	for _, s := range []gpuv1.State{gpuv1.Ready, gpuv1.NotReady, gpuv1.Ignored} {
+		ClusterPolicyState.WithLabelValues(string(s)).Set(boolToFloat(state == s))
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
 }
@@ -0,0 +1,108 @@
+package gpuaddon
+
+import (
+	"errors"
+	"time"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+var _ = Describe("Metrics", func() {
+	getGauge := func(g *prometheus.GaugeVec, labels ...string) float64 {
+		metric := &dto.Metric{}
+		Expect(g.WithLabelValues(labels...).Write(metric)).To(Succeed())
+		return metric.GetGauge().GetValue()
+	}
+
+	getCounter := func(c *prometheus.CounterVec, labels ...string) float64 {
+		metric := &dto.Metric{}
+		Expect(c.WithLabelValues(labels...).Write(metric)).To(Succeed())
+		return metric.GetCounter().GetValue()
+	}
+
+	It("should name the resources after their reconciler", func() {
+		Expect(getResourceName(&ClusterPolicyResourceReconciler{})).To(Equal("ClusterPolicy"))
+		Expect(getResourceName(&NFDResourceReconciler{})).To(Equal("NFD"))
+	})
+
+	It("should record the reason of the failed reconciliations", func() {
+		before := getCounter(ResourceReconcileErrors, "Test", "CreateCrFailed")
+
+		observeResourceReconcile("Test", time.Now(), []metav1.Condition{
+			common.NewCondition("TestDeployed", metav1.ConditionFalse, "CreateCrFailed", "Failed"),
+		}, errors.New("failed"))
+
+		Expect(getCounter(ResourceReconcileErrors, "Test", "CreateCrFailed")).To(Equal(before + 1))
+		Expect(getGauge(ResourceReconcileLastSuccess, "Test")).To(BeZero())
+
+		observeResourceReconcile("Test", time.Now(), nil, nil)
+		Expect(getGauge(ResourceReconcileLastSuccess, "Test")).To(BeNumerically(">", 0))
+	})
+
+	It("should report the current phase and conditions", func() {
+		setPhaseMetric(addonv1alpha1.GPUAddonPhaseReady)
+		Expect(getGauge(AddonPhase, "Ready")).To(Equal(1.0))
+		Expect(getGauge(AddonPhase, "Failed")).To(BeZero())
+
+		setConditionMetrics([]metav1.Condition{
+			common.NewCondition("ClusterPolicyDeployed", metav1.ConditionFalse, "CreateCrFailed", "Failed"),
+		})
+		Expect(getGauge(AddonCondition, "ClusterPolicyDeployed", "False")).To(Equal(1.0))
+		Expect(getGauge(AddonCondition, "ClusterPolicyDeployed", "True")).To(BeZero())
+
+		setClusterPolicyStateMetric(gpuv1.NotReady)
+		Expect(getGauge(ClusterPolicyState, "notReady")).To(Equal(1.0))
+		Expect(getGauge(ClusterPolicyState, "ready")).To(BeZero())
+	})
+
+	It("should count the GPU nodes and their GPUs", func() {
+		gpuNode := func(name string, gpus string) *corev1.Node {
+			return &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   name,
+					Labels: map[string]string{gpuPresentNodeLabel: "true"},
+				},
+				Status: corev1.NodeStatus{
+					Capacity: corev1.ResourceList{
+						gpuResourceName: resource.MustParse(gpus),
+					},
+				},
+			}
+		}
+
+		c := fake.
+			NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithRuntimeObjects(
+				gpuNode("gpu-1", "4"),
+				gpuNode("gpu-2", "8"),
+				&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "cpu-1"}},
+			).
+			Build()
+
+		ch := make(chan prometheus.Metric, 2)
+		(&gpuNodeCollector{client: c}).Collect(ch)
+		close(ch)
+
+		values := []float64{}
+		for m := range ch {
+			metric := &dto.Metric{}
+			Expect(m.Write(metric)).To(Succeed())
+			values = append(values, metric.GetGauge().GetValue())
+		}
+		Expect(values).To(Equal([]float64{2, 12}))
+	})
+})
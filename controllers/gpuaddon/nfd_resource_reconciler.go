@@ -118,6 +118,17 @@ func (r *NFDResourceReconciler) Delete(ctx context.Context, c client.Client) (bo
 	return false, nil
 }
 
+func (r *NFDResourceReconciler) Owned(ctx context.Context, c client.Client) ([]client.Object, error) {
+	return []client.Object{
+		&nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: common.GlobalConfig.AddonNamespace,
+				Name:      common.GlobalConfig.NfdCrName,
+			},
+		},
+	}, nil
+}
+
 func (r *NFDResourceReconciler) getDeployedConditionFetchFailed() metav1.Condition {
 	return common.NewCondition(
 		NFDDeployedCondition,
@@ -0,0 +1,85 @@
+package gpuaddon
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// gpuPresentNodeLabel is set by the GPU feature discovery on the nodes
+	// with NVIDIA GPUs.
+	gpuPresentNodeLabel = "nvidia.com/gpu.present"
+
+	gpuResourceName corev1.ResourceName = "nvidia.com/gpu"
+
+	gpuNodeListTimeout = 10 * time.Second
+)
+
+var (
+	gpuNodesDesc = prometheus.NewDesc(
+		"nvidia_gpuaddon_gpu_nodes",
+		"Number of nodes with NVIDIA GPUs",
+		nil, nil,
+	)
+
+	gpusDesc = prometheus.NewDesc(
+		"nvidia_gpuaddon_gpus",
+		"Number of NVIDIA GPUs advertised by the nodes",
+		nil, nil,
+	)
+)
+
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+
+// gpuNodeCollector counts the GPU nodes and GPUs when the metrics are
+// scraped, so that they follow the nodes joining and leaving the cluster.
+type gpuNodeCollector struct {
+	client client.Reader
+}
+
+var _ prometheus.Collector = &gpuNodeCollector{}
+
+// registerGPUNodeCollector registers the collector of the GPU node metrics,
+// reading the nodes through the given client.
+func registerGPUNodeCollector(c client.Reader) error {
+	err := metrics.Registry.Register(&gpuNodeCollector{client: c})
+	if are := (prometheus.AlreadyRegisteredError{}); errors.As(err, &are) {
+		return nil
+	}
+	return err
+}
+
+func (c *gpuNodeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- gpuNodesDesc
+	ch <- gpusDesc
+}
+
+func (c *gpuNodeCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), gpuNodeListTimeout)
+	defer cancel()
+
+	nodes := &corev1.NodeList{}
+	if err := c.client.List(ctx, nodes, client.MatchingLabels{gpuPresentNodeLabel: "true"}); err != nil {
+		ctrllog.Log.WithName("metrics").Error(err, "Failed to list the GPU nodes")
+		ch <- prometheus.NewInvalidMetric(gpuNodesDesc, err)
+		ch <- prometheus.NewInvalidMetric(gpusDesc, err)
+		return
+	}
+
+	gpus := int64(0)
+	for _, node := range nodes.Items {
+		if capacity, ok := node.Status.Capacity[gpuResourceName]; ok {
+			gpus += capacity.Value()
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(gpuNodesDesc, prometheus.GaugeValue, float64(len(nodes.Items)))
+	ch <- prometheus.MustNewConstMetric(gpusDesc, prometheus.GaugeValue, float64(gpus))
+}
@@ -12,4 +12,7 @@ import (
 type ResourceReconciler interface {
 	Reconcile(ctx context.Context, client client.Client, gpuAddon *addonv1alpha1.GPUAddon) ([]metav1.Condition, error)
 	Delete(ctx context.Context, client client.Client) (bool, error)
+	// Owned returns the resources currently managed by the reconciler, with
+	// their type and key set, to track their deletion.
+	Owned(ctx context.Context, client client.Client) ([]client.Object, error)
 }
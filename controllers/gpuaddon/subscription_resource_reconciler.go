@@ -170,6 +170,27 @@ func (r *SubscriptionResourceReconciler) Delete(ctx context.Context, c client.Cl
 	return true, nil
 }
 
+func (r *SubscriptionResourceReconciler) Owned(ctx context.Context, c client.Client) ([]client.Object, error) {
+	owned := []client.Object{
+		&operatorsv1alpha1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: common.GlobalConfig.AddonNamespace,
+				Name:      subscriptionName,
+			},
+		},
+	}
+
+	csv, err := common.GetCsvWithPrefix(c, common.GlobalConfig.AddonNamespace, packageName)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get GPU Operator CSV %s: %w", packageName, err)
+		}
+		return owned, nil
+	}
+
+	return append(owned, csv), nil
+}
+
 func (r *SubscriptionResourceReconciler) getDeployedConditionFetchFailed() metav1.Condition {
 	return common.NewCondition(
 		SubscriptionDeployedCondition,
@@ -0,0 +1,461 @@
+package gpuaddon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+const (
+	// uninstallCheckInterval is how often the deletion of the resources
+	// managed by the addon is checked.
+	uninstallCheckInterval = 15 * time.Second
+
+	uninstallReportConfigMapName = "gpuaddon-uninstall-report"
+	uninstallReportKey           = "report.json"
+)
+
+// leftoverCRDGroups are the API groups of the CRDs installed along with the
+// operators of the addon, which OLM does not remove with them.
+var leftoverCRDGroups = []string{
+	addonv1alpha1.GroupVersion.Group,
+	gpuv1.GroupVersion.Group,
+	nfdv1.GroupVersion.Group,
+}
+
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get
+//+kubebuilder:rbac:groups="",namespace=system,resources=pods,verbs=list
+
+// ownedResource is a resource managed by the addon, and its deletion state.
+type ownedResource struct {
+	object client.Object
+	status addonv1alpha1.GPUAddonResourceStatus
+}
+
+// uninstallReport lists what the removal of the addon left behind.
+type uninstallReport struct {
+	GeneratedAt metav1.Time `json:"generated_at"`
+	// Resources whose finalizers were removed past the finalizer deadline,
+	// possibly leaving behind what the finalizers were to clean up.
+	FinalizersRemoved []addonv1alpha1.GPUAddonResourceStatus `json:"finalizers_removed"`
+	CRDs              []string                               `json:"crds"`
+	Namespaces        []uninstallReportNamespace             `json:"namespaces"`
+	ConsoleEntries    []string                               `json:"console_entries"`
+}
+
+// uninstallReportNamespace is a namespace left behind, removed along with
+// the addon by the addon manager.
+type uninstallReportNamespace struct {
+	Name  string                `json:"name"`
+	Phase corev1.NamespacePhase `json:"phase"`
+	Pods  []string              `json:"pods"`
+}
+
+// uninstall removes the resources managed by the addon and reports their
+// deletion in the GPUAddon status. Past the finalizer deadline, the
+// finalizers of the resources still being deleted are removed. The addon
+// operator itself is removed by the uninstall of the ConfigMap controller,
+// once the other operators are. It returns
+// whether the GPUAddon finalizer can be removed, and if not when to check
+// again.
+func (r *GPUAddonReconciler) uninstall(ctx context.Context, gpuAddon *addonv1alpha1.GPUAddon) (bool, time.Duration, error) {
+	logger := log.FromContext(ctx, "Reconcile Step", "Uninstall")
+
+	if err := r.removeOwnedResources(ctx); err != nil {
+		return false, 0, err
+	}
+
+	resources, err := r.getOwnedResources(ctx, gpuAddon.Status.Uninstall)
+	if err != nil {
+		return false, 0, err
+	}
+
+	timeout := addonv1alpha1.GPUAddonDefaultFinalizerTimeout
+	if gpuAddon.Spec.FinalizerTimeout != nil {
+		timeout = gpuAddon.Spec.FinalizerTimeout.Duration
+	}
+	start := gpuAddon.DeletionTimestamp.Time
+	deadline := start.Add(timeout)
+
+	now := time.Now()
+	if !now.Before(deadline) {
+		if err := r.removeStuckFinalizers(ctx, resources); err != nil {
+			return false, 0, err
+		}
+	}
+
+	status := &addonv1alpha1.GPUAddonUninstallStatus{
+		StartTime:         metav1.NewTime(start),
+		FinalizerDeadline: metav1.NewTime(deadline),
+		Resources:         make([]addonv1alpha1.GPUAddonResourceStatus, 0, len(resources)),
+	}
+	pending := []string{}
+	for _, res := range resources {
+		status.Resources = append(status.Resources, res.status)
+		if res.status.State == addonv1alpha1.GPUAddonResourceStatePending ||
+			res.status.State == addonv1alpha1.GPUAddonResourceStateDeleting {
+			pending = append(pending, fmt.Sprintf("%s %s", res.status.Kind, res.status.Name))
+		}
+	}
+
+	if err := r.patchUninstallStatus(ctx, gpuAddon, status); err != nil {
+		return false, 0, err
+	}
+
+	if len(pending) > 0 {
+		requeue := uninstallCheckInterval
+		if remaining := deadline.Sub(now); remaining > 0 && remaining < requeue {
+			requeue = remaining
+		}
+		logger.Info("Waiting for the resources managed by the addon to be deleted",
+			"pending", pending,
+			"finalizerDeadline", deadline)
+		return false, requeue, nil
+	}
+
+	// The report is informational, it does not hold back the removal.
+	if err := writeUninstallReport(ctx, r.Client, status); err != nil {
+		logger.Error(err, "Failed to write the uninstall report")
+	}
+
+	return true, 0, nil
+}
+
+// getOwnedResources returns the resources managed by the addon with their
+// deletion state. The resources in the previous status which are not managed
+// anymore, e.g. a deleted CSV, are reported as deleted.
+func (r *GPUAddonReconciler) getOwnedResources(
+	ctx context.Context,
+	previous *addonv1alpha1.GPUAddonUninstallStatus) ([]ownedResource, error) {
+
+	previousStates := map[string]addonv1alpha1.GPUAddonResourceState{}
+	if previous != nil {
+		for _, s := range previous.Resources {
+			previousStates[getResourceStatusKey(s)] = s.State
+		}
+	}
+
+	resources := []ownedResource{}
+	for _, rr := range resourceOrderedReconcilers {
+		owned, err := rr.Owned(ctx, r.Client)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range owned {
+			res, err := r.getOwnedResource(ctx, obj)
+			if err != nil {
+				return nil, err
+			}
+
+			key := getResourceStatusKey(res.status)
+			if previousStates[key] == addonv1alpha1.GPUAddonResourceStateFinalizersRemoved &&
+				res.status.State == addonv1alpha1.GPUAddonResourceStateDeleted {
+				res.status.State = addonv1alpha1.GPUAddonResourceStateFinalizersRemoved
+			}
+			delete(previousStates, key)
+
+			resources = append(resources, res)
+		}
+	}
+
+	if previous != nil {
+		for _, s := range previous.Resources {
+			if _, ok := previousStates[getResourceStatusKey(s)]; !ok {
+				continue
+			}
+			if s.State != addonv1alpha1.GPUAddonResourceStateFinalizersRemoved {
+				s.State = addonv1alpha1.GPUAddonResourceStateDeleted
+			}
+			s.Finalizers = nil
+			resources = append(resources, ownedResource{status: s})
+		}
+	}
+
+	return resources, nil
+}
+
+func (r *GPUAddonReconciler) getOwnedResource(ctx context.Context, obj client.Object) (ownedResource, error) {
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+	if err != nil {
+		return ownedResource{}, err
+	}
+
+	res := ownedResource{
+		object: obj,
+		status: addonv1alpha1.GPUAddonResourceStatus{
+			Kind:      gvk.Kind,
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+		},
+	}
+
+	err = r.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+	switch {
+	case k8serrors.IsNotFound(err) || meta.IsNoMatchError(err):
+		res.status.State = addonv1alpha1.GPUAddonResourceStateDeleted
+	case err != nil:
+		return ownedResource{}, fmt.Errorf("failed to get %s %s: %w", gvk.Kind, obj.GetName(), err)
+	case obj.GetDeletionTimestamp() != nil:
+		res.status.State = addonv1alpha1.GPUAddonResourceStateDeleting
+		res.status.Finalizers = obj.GetFinalizers()
+	default:
+		res.status.State = addonv1alpha1.GPUAddonResourceStatePending
+	}
+
+	return res, nil
+}
+
+func getResourceStatusKey(s addonv1alpha1.GPUAddonResourceStatus) string {
+	return fmt.Sprintf("%s/%s/%s", s.Kind, s.Namespace, s.Name)
+}
+
+// removeStuckFinalizers removes the finalizers of the resources being
+// deleted, so that the removal of the addon is not held back forever by
+// their controllers, e.g. when these were removed first.
+func (r *GPUAddonReconciler) removeStuckFinalizers(ctx context.Context, resources []ownedResource) error {
+	logger := log.FromContext(ctx, "Reconcile Step", "Uninstall")
+
+	for i := range resources {
+		res := &resources[i]
+		if res.status.State != addonv1alpha1.GPUAddonResourceStateDeleting {
+			continue
+		}
+
+		patch := client.MergeFrom(res.object.DeepCopyObject().(client.Object))
+		for _, f := range res.object.GetFinalizers() {
+			controllerutil.RemoveFinalizer(res.object, f)
+		}
+		if err := r.Patch(ctx, res.object, patch); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to remove the finalizers of %s %s: %w", res.status.Kind, res.status.Name, err)
+		}
+
+		logger.Info("Removed the finalizers of a resource past the finalizer deadline",
+			"kind", res.status.Kind,
+			"namespace", res.status.Namespace,
+			"name", res.status.Name,
+			"finalizers", res.status.Finalizers)
+
+		res.status.State = addonv1alpha1.GPUAddonResourceStateFinalizersRemoved
+	}
+
+	return nil
+}
+
+func (r *GPUAddonReconciler) patchUninstallStatus(
+	ctx context.Context,
+	gpuAddon *addonv1alpha1.GPUAddon,
+	status *addonv1alpha1.GPUAddonUninstallStatus) error {
+
+	patch := client.MergeFrom(gpuAddon.DeepCopy())
+	gpuAddon.Status.Phase = addonv1alpha1.GPUAddonPhaseUninstalling
+	gpuAddon.Status.Uninstall = status
+
+	if err := r.Status().Patch(ctx, gpuAddon, patch); err != nil {
+		return fmt.Errorf("failed to patch status: %w", err)
+	}
+
+	setPhaseMetric(gpuAddon.Status.Phase)
+
+	return nil
+}
+
+// writeUninstallReport writes what the removal of the resources managed by
+// the addon left behind in a ConfigMap. It is not owned by the GPUAddon CR so
+// that it outlives it, until the addon namespace is removed.
+func writeUninstallReport(ctx context.Context, c client.Client, status *addonv1alpha1.GPUAddonUninstallStatus) error {
+	report := &uninstallReport{
+		FinalizersRemoved: []addonv1alpha1.GPUAddonResourceStatus{},
+	}
+	for _, s := range status.Resources {
+		if s.State == addonv1alpha1.GPUAddonResourceStateFinalizersRemoved {
+			report.FinalizersRemoved = append(report.FinalizersRemoved, s)
+		}
+	}
+
+	return saveUninstallReport(ctx, c, report)
+}
+
+// RefreshUninstallReport lists again what the removal of the addon left
+// behind in the uninstall report, e.g. once the CRDs have been removed. The
+// report is deleted along with the addon namespace, so the leftovers are also
+// recorded in an Event of the addon namespace, which is cluster-scoped and
+// thus has its Events in the default namespace.
+func RefreshUninstallReport(ctx context.Context, c client.Client, recorder record.EventRecorder) error {
+	cm := &corev1.ConfigMap{}
+	err := c.Get(ctx, client.ObjectKey{
+		Name:      uninstallReportConfigMapName,
+		Namespace: common.GlobalConfig.AddonNamespace,
+	}, cm)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get ConfigMap %s: %w", uninstallReportConfigMapName, err)
+	}
+
+	report := &uninstallReport{}
+	if data, ok := cm.Data[uninstallReportKey]; ok {
+		if err := json.Unmarshal([]byte(data), report); err != nil {
+			return fmt.Errorf("failed to unmarshal the uninstall report: %w", err)
+		}
+	}
+	if report.FinalizersRemoved == nil {
+		report.FinalizersRemoved = []addonv1alpha1.GPUAddonResourceStatus{}
+	}
+
+	if err := saveUninstallReport(ctx, c, report); err != nil {
+		return err
+	}
+
+	leftovers := getUninstallLeftovers(report)
+	if len(leftovers) == 0 {
+		return nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, client.ObjectKey{Name: common.GlobalConfig.AddonNamespace}, ns); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get namespace %s: %w", common.GlobalConfig.AddonNamespace, err)
+	}
+
+	recorder.Event(ns, corev1.EventTypeWarning, "UninstallLeftovers",
+		fmt.Sprintf("The removal of the addon left behind: %s", strings.Join(leftovers, ", ")))
+
+	return nil
+}
+
+func saveUninstallReport(ctx context.Context, c client.Client, report *uninstallReport) error {
+	logger := log.FromContext(ctx, "Reconcile Step", "Uninstall Report")
+
+	if err := getLeftovers(ctx, c, report); err != nil {
+		return err
+	}
+	report.GeneratedAt = metav1.Now()
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the uninstall report: %w", err)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      uninstallReportConfigMapName,
+			Namespace: common.GlobalConfig.AddonNamespace,
+		},
+	}
+
+	res, err := controllerutil.CreateOrPatch(ctx, c, cm, func() error {
+		cm.Data = map[string]string{
+			uninstallReportKey: string(data),
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Uninstall report written successfully",
+		"name", cm.Name,
+		"leftovers", getUninstallLeftovers(report),
+		"result", res)
+
+	return nil
+}
+
+// getUninstallLeftovers describes what the removal of the addon left behind,
+// besides the addon namespace which the addon manager removes afterwards.
+func getUninstallLeftovers(report *uninstallReport) []string {
+	leftovers := []string{}
+	for _, s := range report.FinalizersRemoved {
+		name := s.Name
+		if s.Namespace != "" {
+			name = fmt.Sprintf("%s/%s", s.Namespace, s.Name)
+		}
+		leftovers = append(leftovers, fmt.Sprintf("%s %s whose finalizers %v were removed",
+			s.Kind, name, s.Finalizers))
+	}
+	for _, crd := range report.CRDs {
+		leftovers = append(leftovers, fmt.Sprintf("CRD %s", crd))
+	}
+	leftovers = append(leftovers, report.ConsoleEntries...)
+
+	return leftovers
+}
+
+// getLeftovers lists the CRDs, namespaces and console entries left behind
+// in the report.
+func getLeftovers(ctx context.Context, c client.Client, report *uninstallReport) error {
+	report.CRDs = []string{}
+	report.Namespaces = []uninstallReportNamespace{}
+	report.ConsoleEntries = []string{}
+
+	crds := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := c.List(ctx, crds); err != nil {
+		return fmt.Errorf("failed to list the CRDs: %w", err)
+	}
+	for _, crd := range crds.Items {
+		if common.SliceContainsString(leftoverCRDGroups, crd.Spec.Group) {
+			report.CRDs = append(report.CRDs, crd.Name)
+		}
+	}
+	sort.Strings(report.CRDs)
+
+	// The addon namespace, where the operator runs, is removed by the addon
+	// manager after the operator.
+	ns := &corev1.Namespace{}
+	err := c.Get(ctx, client.ObjectKey{Name: common.GlobalConfig.AddonNamespace}, ns)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get namespace %s: %w", common.GlobalConfig.AddonNamespace, err)
+	}
+	if err == nil {
+		pods := &corev1.PodList{}
+		if err := c.List(ctx, pods, client.InNamespace(ns.Name)); err != nil {
+			return fmt.Errorf("failed to list the pods in %s: %w", ns.Name, err)
+		}
+
+		namespace := uninstallReportNamespace{
+			Name:  ns.Name,
+			Phase: ns.Status.Phase,
+			Pods:  []string{},
+		}
+		for _, pod := range pods.Items {
+			namespace.Pods = append(namespace.Pods, pod.Name)
+		}
+		sort.Strings(namespace.Pods)
+
+		report.Namespaces = append(report.Namespaces, namespace)
+	}
+
+	console := &operatorv1.Console{}
+	err = c.Get(ctx, client.ObjectKey{Name: "cluster"}, console)
+	if err != nil && !k8serrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to get the cluster console: %w", err)
+	}
+	if err == nil && common.SliceContainsString(console.Spec.Plugins, consolePluginName) {
+		report.ConsoleEntries = append(report.ConsoleEntries,
+			fmt.Sprintf("plugin %s enabled in consoles.operator.openshift.io/cluster", consolePluginName))
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package gpuaddon
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+var _ = Describe("Uninstall", func() {
+	common.ProcessConfig()
+
+	newDeletedGPUAddon := func(deletedAt time.Time) *addonv1alpha1.GPUAddon {
+		deletionTimestamp := metav1.NewTime(deletedAt)
+		return &addonv1alpha1.GPUAddon{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "addon",
+				Namespace:         common.GlobalConfig.AddonNamespace,
+				Finalizers:        []string{common.GlobalConfig.AddonID},
+				DeletionTimestamp: &deletionTimestamp,
+			},
+		}
+	}
+
+	newStuckClusterPolicy := func() *gpuv1.ClusterPolicy {
+		return &gpuv1.ClusterPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       common.GlobalConfig.ClusterPolicyName,
+				Finalizers: []string{"nvidia.com/finalizer"},
+			},
+		}
+	}
+
+	reconcileDeletion := func(r *GPUAddonReconciler, g *addonv1alpha1.GPUAddon) reconcile.Result {
+		res, err := r.Reconcile(context.TODO(), reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: g.Name, Namespace: g.Namespace},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		return res
+	}
+
+	It("should report the resources still being deleted", func() {
+		g := newDeletedGPUAddon(time.Now())
+		r := newTestGPUAddonReconciler(g, newStuckClusterPolicy())
+
+		res := reconcileDeletion(r, g)
+		Expect(res.RequeueAfter).To(Equal(uninstallCheckInterval))
+
+		updated := &addonv1alpha1.GPUAddon{}
+		Expect(r.Get(context.TODO(), client.ObjectKeyFromObject(g), updated)).To(Succeed())
+		Expect(controllerutil.ContainsFinalizer(updated, common.GlobalConfig.AddonID)).To(BeTrue())
+		Expect(updated.Status.Phase).To(Equal(addonv1alpha1.GPUAddonPhaseUninstalling))
+		Expect(updated.Status.Uninstall).ToNot(BeNil())
+		Expect(updated.Status.Uninstall.FinalizerDeadline.Time).To(
+			BeTemporally("~", updated.DeletionTimestamp.Add(addonv1alpha1.GPUAddonDefaultFinalizerTimeout), time.Second))
+		Expect(updated.Status.Uninstall.Resources).To(ContainElement(addonv1alpha1.GPUAddonResourceStatus{
+			Kind:       "ClusterPolicy",
+			Name:       common.GlobalConfig.ClusterPolicyName,
+			State:      addonv1alpha1.GPUAddonResourceStateDeleting,
+			Finalizers: []string{"nvidia.com/finalizer"},
+		}))
+		Expect(updated.Status.Uninstall.Resources).To(ContainElement(addonv1alpha1.GPUAddonResourceStatus{
+			Kind:      "NodeFeatureDiscovery",
+			Namespace: common.GlobalConfig.AddonNamespace,
+			Name:      common.GlobalConfig.NfdCrName,
+			State:     addonv1alpha1.GPUAddonResourceStateDeleted,
+		}))
+
+	})
+
+	It("should remove the finalizers past the deadline and report what is left behind", func() {
+		g := newDeletedGPUAddon(time.Now().Add(-time.Hour))
+		r := newTestGPUAddonReconciler(g, newStuckClusterPolicy(),
+			&apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "clusterpolicies.nvidia.com"},
+				Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Group: "nvidia.com"},
+			},
+			&apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "foos.example.com"},
+				Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Group: "example.com"},
+			},
+			&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: common.GlobalConfig.AddonNamespace},
+				Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "nvidia-driver", Namespace: common.GlobalConfig.AddonNamespace},
+			},
+			&operatorv1.Console{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec:       operatorv1.ConsoleSpec{Plugins: []string{consolePluginName}},
+			},
+		)
+
+		res := reconcileDeletion(r, g)
+		Expect(res.RequeueAfter).To(BeZero())
+
+		err := r.Get(context.TODO(), client.ObjectKeyFromObject(g), &addonv1alpha1.GPUAddon{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+
+		cp := &gpuv1.ClusterPolicy{}
+		err = r.Get(context.TODO(), client.ObjectKey{Name: common.GlobalConfig.ClusterPolicyName}, cp)
+		if err == nil {
+			Expect(cp.Finalizers).To(BeEmpty())
+		} else {
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		}
+
+		cm := &corev1.ConfigMap{}
+		Expect(r.Get(context.TODO(), client.ObjectKey{
+			Name:      uninstallReportConfigMapName,
+			Namespace: common.GlobalConfig.AddonNamespace,
+		}, cm)).To(Succeed())
+		Expect(cm.OwnerReferences).To(BeEmpty())
+
+		report := &uninstallReport{}
+		Expect(json.Unmarshal([]byte(cm.Data[uninstallReportKey]), report)).To(Succeed())
+		Expect(report.FinalizersRemoved).To(ConsistOf(addonv1alpha1.GPUAddonResourceStatus{
+			Kind:       "ClusterPolicy",
+			Name:       common.GlobalConfig.ClusterPolicyName,
+			State:      addonv1alpha1.GPUAddonResourceStateFinalizersRemoved,
+			Finalizers: []string{"nvidia.com/finalizer"},
+		}))
+		Expect(report.CRDs).To(Equal([]string{"clusterpolicies.nvidia.com"}))
+		Expect(report.Namespaces).To(Equal([]uninstallReportNamespace{{
+			Name:  common.GlobalConfig.AddonNamespace,
+			Phase: corev1.NamespaceActive,
+			Pods:  []string{"nvidia-driver"},
+		}}))
+		Expect(report.ConsoleEntries).To(HaveLen(1))
+	})
+
+	It("should record the leftovers in an Event outliving the addon namespace", func() {
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: common.GlobalConfig.AddonNamespace},
+		}
+		r := newTestGPUAddonReconciler(ns,
+			&apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "clusterpolicies.nvidia.com"},
+				Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Group: "nvidia.com"},
+			},
+		)
+		recorder := record.NewFakeRecorder(10)
+
+		Expect(RefreshUninstallReport(context.TODO(), r.Client, recorder)).To(Succeed())
+		Expect(recorder.Events).To(HaveLen(1))
+		Expect(<-recorder.Events).To(Equal(
+			"Warning UninstallLeftovers The removal of the addon left behind: CRD clusterpolicies.nvidia.com"))
+	})
+
+	It("should not record an Event without leftovers", func() {
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: common.GlobalConfig.AddonNamespace},
+		}
+		r := newTestGPUAddonReconciler(ns)
+		recorder := record.NewFakeRecorder(10)
+
+		Expect(RefreshUninstallReport(context.TODO(), r.Client, recorder)).To(Succeed())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("should keep reporting the resources whose finalizers were removed", func() {
+		r := newTestGPUAddonReconciler()
+
+		resources, err := r.getOwnedResources(context.TODO(), &addonv1alpha1.GPUAddonUninstallStatus{
+			Resources: []addonv1alpha1.GPUAddonResourceStatus{
+				{
+					Kind:  "ClusterPolicy",
+					Name:  common.GlobalConfig.ClusterPolicyName,
+					State: addonv1alpha1.GPUAddonResourceStateFinalizersRemoved,
+				},
+				{
+					Kind:      "ClusterServiceVersion",
+					Namespace: common.GlobalConfig.AddonNamespace,
+					Name:      "gpu-operator-certified.v1.10.1",
+					State:     addonv1alpha1.GPUAddonResourceStateDeleting,
+				},
+			},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		states := map[string]addonv1alpha1.GPUAddonResourceState{}
+		for _, res := range resources {
+			states[res.status.Kind] = res.status.State
+		}
+		Expect(states).To(HaveKeyWithValue("ClusterPolicy", addonv1alpha1.GPUAddonResourceStateFinalizersRemoved))
+		Expect(states).To(HaveKeyWithValue("ClusterServiceVersion", addonv1alpha1.GPUAddonResourceStateDeleted))
+		Expect(states).To(HaveKeyWithValue("Subscription", addonv1alpha1.GPUAddonResourceStateDeleted))
+	})
+})
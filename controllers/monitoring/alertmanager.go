@@ -1,10 +1,13 @@
 package monitoring
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
@@ -19,6 +22,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
 
 	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
 	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
@@ -32,11 +36,31 @@ const (
 	pagerDutyKey = "PAGERDUTY_KEY"
 
 	snitchURLKey = "SNITCH_URL"
+
+	nullReceiverName = addonv1alpha1.MonitoringNullReceiverName
+
+	pagerDutyReceiverName = addonv1alpha1.MonitoringPagerDutyReceiverName
+
+	deadMansSnitchReceiverName = addonv1alpha1.MonitoringDeadMansSnitchReceiverName
+
+	// The prometheus-operator renders the configuration it loads into the
+	// Alertmanager in this Secret, compressed by recent versions.
+	alertManagerGeneratedConfigSecretFormat = "alertmanager-%s-generated"
+	alertManagerGeneratedConfigKey          = "alertmanager.yaml"
+	alertManagerGeneratedConfigGzipKey      = "alertmanager.yaml.gz"
+
+	// pagerDutyDescription prefixes the summary of the PagerDuty incidents
+	// with the cluster they fired on.
+	pagerDutyDescription = "[{{ .CommonLabels.infrastructure_name }}] " +
+		"{{ .CommonLabels.alertname }}: {{ .CommonAnnotations.summary }}"
 )
 
 var (
 	pagerdutyAlerts = []string{
 		"NVIDIAGPUAddonGPUOperatorSubscriptionInstallationPending",
+		"NVIDIAGPUAddonGPUFallenOffBus",
+		"NVIDIAGPUAddonGPUEccDoubleBitErrors",
+		"NVIDIAGPUAddonGPURowRemapFailure",
 	}
 )
 
@@ -94,11 +118,23 @@ func (r *MonitoringReconciler) setDesiredAlertManager(
 		return errors.New("alertManager cannot be nil")
 	}
 
+	spec := m.Spec.Alertmanager
+
 	alertManager.Spec = promv1.AlertmanagerSpec{}
 
-	replicas := int32(3)
+	replicas := addonv1alpha1.MonitoringDefaultAlertmanagerReplicas
+	if spec.Replicas != nil {
+		replicas = *spec.Replicas
+	}
 	alertManager.Spec.Replicas = &replicas
 
+	alertManager.Spec.Retention = addonv1alpha1.MonitoringDefaultAlertmanagerRetentionTime
+	if spec.RetentionTime != "" {
+		alertManager.Spec.Retention = spec.RetentionTime
+	}
+
+	alertManager.Spec.Storage = getStorageSpec(spec.VolumeClaimTemplate)
+
 	alertManager.Spec.Resources = corev1.ResourceRequirements{
 		Limits: corev1.ResourceList{
 			"cpu":    resource.MustParse("100m"),
@@ -109,6 +145,14 @@ func (r *MonitoringReconciler) setDesiredAlertManager(
 			"memory": resource.MustParse("200Mi"),
 		},
 	}
+	if spec.Resources != nil {
+		alertManager.Spec.Resources = *spec.Resources.DeepCopy()
+	}
+
+	// Only the AlertmanagerConfig of the addon is loaded.
+	alertManager.Spec.AlertmanagerConfigSelector = &metav1.LabelSelector{
+		MatchLabels: getAlertManagerConfigLabels(),
+	}
 
 	alertManager.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{
 		{
@@ -145,9 +189,12 @@ func (r *MonitoringReconciler) deleteAlertManager(
 	return nil
 }
 
+// reconcileAlertManagerConfig routes the addon alerts to the built-in
+// receivers. Receivers whose Secret is missing or incomplete are omitted and
+// returned, so that the rest of the monitoring stack is still reconciled.
 func (r *MonitoringReconciler) reconcileAlertManagerConfig(
 	ctx context.Context,
-	m *addonv1alpha1.Monitoring) error {
+	m *addonv1alpha1.Monitoring) (*alertReceivers, error) {
 
 	logger := log.FromContext(ctx, "Reconcile Step", "AlertManagerConfig CR")
 
@@ -161,7 +208,7 @@ func (r *MonitoringReconciler) reconcileAlertManagerConfig(
 
 	exists := !k8serrors.IsNotFound(err)
 	if err != nil && !k8serrors.IsNotFound(err) {
-		return err
+		return nil, err
 	}
 
 	alertManagerConfig := &promv1alpha1.AlertmanagerConfig{
@@ -175,22 +222,20 @@ func (r *MonitoringReconciler) reconcileAlertManagerConfig(
 		alertManagerConfig = existingAMC
 	}
 
-	if err := r.checkPagerDutyServiceKey(ctx, common.GlobalConfig.PagerDutySecretName, m.Namespace); err != nil {
-		return err
-	}
-
-	deadMansSnitchURL, err := r.getDeadMansSnitchURL(ctx, common.GlobalConfig.DeadMansSnitchSecretName, m.Namespace)
+	receivers, err := r.getAlertReceivers(ctx, m)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	pagerDutySecretName := common.GlobalConfig.PagerDutySecretName
+	for name, reason := range receivers.notConfigured {
+		logger.Info("Alert receiver not configured, omitting it", "receiver", name, "reason", reason)
+	}
 
 	res, err := controllerutil.CreateOrPatch(context.TODO(), r.Client, alertManagerConfig, func() error {
-		return r.setDesiredAlertManagerConfig(r.Client, alertManagerConfig, pagerDutySecretName, deadMansSnitchURL, m)
+		return r.setDesiredAlertManagerConfig(r.Client, alertManagerConfig, receivers, m)
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	logger.Info("AlertManagerConfig reconciled successfully",
@@ -198,39 +243,28 @@ func (r *MonitoringReconciler) reconcileAlertManagerConfig(
 		"namespace", alertManagerConfig.Namespace,
 		"result", res)
 
-	return nil
+	return receivers, nil
 }
 
 func (r *MonitoringReconciler) setDesiredAlertManagerConfig(
 	c client.Client,
 	alertManagerConfig *promv1alpha1.AlertmanagerConfig,
-	pagerDutySecretName string,
-	deadMansSnitchURL string,
+	receivers *alertReceivers,
 	m *addonv1alpha1.Monitoring) error {
 
 	if alertManagerConfig == nil {
 		return errors.New("alertManagerConfig cannot be nil")
 	}
 
-	pagerDutyRoute, err := convertToApiExtV1JSON(promv1alpha1.Route{
-		GroupBy:        []string{"alertname"},
-		GroupWait:      "30s",
-		GroupInterval:  "5m",
-		RepeatInterval: "12h",
-		Matchers: []promv1alpha1.Matcher{
-			{
-				Name:      "alertname",
-				Value:     getRegexMatcher(pagerdutyAlerts),
-				MatchType: promv1alpha1.MatchRegexp,
-			},
+	configuredReceivers := []promv1alpha1.Receiver{
+		{
+			Name: nullReceiverName,
 		},
-		Receiver: "pagerduty",
-	})
-	if err != nil {
-		return err
 	}
 
-	deadMansSnitchRoute, err := convertToApiExtV1JSON(promv1alpha1.Route{
+	// The watchdog alert is always routed first, so that it never reaches
+	// the other receivers, even when Dead Man's Snitch is not configured.
+	deadMansSnitchRoute := promv1alpha1.Route{
 		GroupBy:        []string{"alertname"},
 		GroupWait:      "30s",
 		GroupInterval:  "5m",
@@ -238,47 +272,117 @@ func (r *MonitoringReconciler) setDesiredAlertManagerConfig(
 		Matchers: []promv1alpha1.Matcher{
 			{
 				Name:      "alertname",
-				Value:     "DeadMansSnitch",
+				Value:     deadMansSnitchReceiverName,
 				MatchType: promv1alpha1.MatchEqual,
 			},
 		},
-		Receiver: "DeadMansSnitch",
-	})
-	if err != nil {
-		return err
+		Receiver: nullReceiverName,
 	}
-
-	alertManagerConfig.Spec = promv1alpha1.AlertmanagerConfigSpec{}
-	alertManagerConfig.Spec.Route = &promv1alpha1.Route{
-		Receiver: "null",
-		Routes: []apiextensionsv1.JSON{
-			pagerDutyRoute,
-			deadMansSnitchRoute,
-		},
+	if receivers.deadMansSnitchURL != "" {
+		deadMansSnitchURL := receivers.deadMansSnitchURL
+		deadMansSnitchRoute.Receiver = deadMansSnitchReceiverName
+		configuredReceivers = append(configuredReceivers, promv1alpha1.Receiver{
+			Name:           deadMansSnitchReceiverName,
+			WebhookConfigs: []promv1alpha1.WebhookConfig{{URL: &deadMansSnitchURL}},
+		})
 	}
-
-	alertManagerConfig.Spec.Receivers = []promv1alpha1.Receiver{
-		{
-			Name: "null",
-		},
-		{
-			Name: "pagerduty",
+	routes := []promv1alpha1.Route{deadMansSnitchRoute}
+
+	if receivers.pagerDutySecretName != "" {
+		// The paged alerts may also be routed to the user receivers.
+		pagerDutyRoute := getPagerDutyRoute(m.Spec.PagerDuty)
+		routes = append(routes, promv1alpha1.Route{
+			GroupBy:        pagerDutyRoute.GroupBy,
+			GroupWait:      pagerDutyRoute.GroupWait,
+			GroupInterval:  pagerDutyRoute.GroupInterval,
+			RepeatInterval: pagerDutyRoute.RepeatInterval,
+			Matchers: []promv1alpha1.Matcher{
+				{
+					Name:      "alertname",
+					Value:     getRegexMatcher(pagerdutyAlerts),
+					MatchType: promv1alpha1.MatchRegexp,
+				},
+			},
+			Receiver: pagerDutyReceiverName,
+			Continue: true,
+		})
+		configuredReceivers = append(configuredReceivers, promv1alpha1.Receiver{
+			Name: pagerDutyReceiverName,
 			PagerDutyConfigs: []promv1alpha1.PagerDutyConfig{{
 				ServiceKey: &corev1.SecretKeySelector{
 					Key:                  pagerDutyKey,
-					LocalObjectReference: corev1.LocalObjectReference{Name: pagerDutySecretName},
+					LocalObjectReference: corev1.LocalObjectReference{Name: receivers.pagerDutySecretName},
 				},
+				Description: pagerDutyDescription,
+				Details:     getPagerDutyDetails(),
 			}},
-		},
-		{
-			Name:           "DeadMansSnitch",
-			WebhookConfigs: []promv1alpha1.WebhookConfig{{URL: &deadMansSnitchURL}},
-		},
+		})
+	}
+
+	routes = append(routes, receivers.userRoutes...)
+	configuredReceivers = append(configuredReceivers, receivers.user...)
+
+	childRoutes := make([]apiextensionsv1.JSON, 0, len(routes))
+	for _, route := range routes {
+		childRoute, err := convertToApiExtV1JSON(route)
+		if err != nil {
+			return err
+		}
+		childRoutes = append(childRoutes, childRoute)
+	}
+
+	if alertManagerConfig.Labels == nil {
+		alertManagerConfig.Labels = map[string]string{}
 	}
+	for k, v := range getAlertManagerConfigLabels() {
+		alertManagerConfig.Labels[k] = v
+	}
+
+	alertManagerConfig.Spec = promv1alpha1.AlertmanagerConfigSpec{}
+	alertManagerConfig.Spec.Route = &promv1alpha1.Route{
+		Receiver: nullReceiverName,
+		Routes:   childRoutes,
+	}
+	alertManagerConfig.Spec.Receivers = configuredReceivers
+	alertManagerConfig.Spec.InhibitRules = getInhibitRules(m)
 
 	return ctrl.SetControllerReference(m, alertManagerConfig, c.Scheme())
 }
 
+// getPagerDutyDetails adds the cluster identity, set as external labels of
+// the addon Prometheus, to the details of the PagerDuty incidents.
+func getPagerDutyDetails() []promv1alpha1.KeyValue {
+	details := []promv1alpha1.KeyValue{}
+	for _, label := range []string{clusterIDLabel, infrastructureNameLabel, addonVersionLabel} {
+		details = append(details, promv1alpha1.KeyValue{
+			Key:   label,
+			Value: fmt.Sprintf("{{ .CommonLabels.%s }}", label),
+		})
+	}
+	return details
+}
+
+// getPagerDutyRoute fills in the grouping of the PagerDuty route left unset
+// in the MonitoringSpec.
+func getPagerDutyRoute(spec addonv1alpha1.MonitoringPagerDutySpec) addonv1alpha1.MonitoringPagerDutySpec {
+	route := *spec.DeepCopy()
+
+	if len(route.GroupBy) == 0 {
+		route.GroupBy = []string{addonv1alpha1.MonitoringDefaultPagerDutyGroupBy}
+	}
+	if route.GroupWait == "" {
+		route.GroupWait = addonv1alpha1.MonitoringDefaultPagerDutyGroupWait
+	}
+	if route.GroupInterval == "" {
+		route.GroupInterval = addonv1alpha1.MonitoringDefaultPagerDutyGroupInterval
+	}
+	if route.RepeatInterval == "" {
+		route.RepeatInterval = addonv1alpha1.MonitoringDefaultPagerDutyRepeatInterval
+	}
+
+	return route
+}
+
 func (r *MonitoringReconciler) deleteAlertManagerConfig(
 	ctx context.Context,
 	m *addonv1alpha1.Monitoring) error {
@@ -298,22 +402,86 @@ func (r *MonitoringReconciler) deleteAlertManagerConfig(
 	return nil
 }
 
+// alertReceivers holds the configuration of the built-in alert receivers
+// read from their Secrets, and of the receivers and routes of the
+// MonitoringSpec. A receiver is omitted when its Secret is missing or
+// incomplete, the reason being recorded in notConfigured by receiver name.
+type alertReceivers struct {
+	pagerDutySecretName string
+	deadMansSnitchURL   string
+	user                []promv1alpha1.Receiver
+	userRoutes          []promv1alpha1.Route
+	notConfigured       map[string]string
+}
+
+// secretNotConfiguredError reports a missing or incomplete Secret referenced
+// by the monitoring stack, as opposed to a failure to read it.
+type secretNotConfiguredError struct {
+	reason string
+}
+
+func (e *secretNotConfiguredError) Error() string {
+	return e.reason
+}
+
+func (r *MonitoringReconciler) getAlertReceivers(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring) (*alertReceivers, error) {
+
+	receivers := &alertReceivers{
+		notConfigured: map[string]string{},
+	}
+	namespace := m.Namespace
+
+	var notConfiguredErr *secretNotConfiguredError
+
+	err := r.checkPagerDutyServiceKey(ctx, common.GlobalConfig.PagerDutySecretName, namespace)
+	switch {
+	case err == nil:
+		receivers.pagerDutySecretName = common.GlobalConfig.PagerDutySecretName
+	case errors.As(err, &notConfiguredErr):
+		receivers.notConfigured[pagerDutyReceiverName] = err.Error()
+	default:
+		return nil, err
+	}
+
+	receivers.deadMansSnitchURL, err = r.getDeadMansSnitchURL(ctx, common.GlobalConfig.DeadMansSnitchSecretName, namespace)
+	switch {
+	case err == nil:
+	case errors.As(err, &notConfiguredErr):
+		receivers.notConfigured[deadMansSnitchReceiverName] = err.Error()
+	default:
+		return nil, err
+	}
+
+	receivers.user, err = r.getUserReceivers(ctx, m, receivers.notConfigured)
+	if err != nil {
+		return nil, err
+	}
+
+	receivers.userRoutes = getUserRoutes(m, receivers.user, receivers.notConfigured)
+
+	return receivers, nil
+}
+
 func (r *MonitoringReconciler) checkPagerDutyServiceKey(ctx context.Context, secretName, namespace string) error {
 	pagerDutySecret := &corev1.Secret{}
 	if err := r.Get(ctx, types.NamespacedName{
 		Name:      secretName,
 		Namespace: namespace,
 	}, pagerDutySecret); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return &secretNotConfiguredError{
+				reason: fmt.Sprintf("PagerDuty secret %s not found in %s", secretName, namespace),
+			}
+		}
 		return fmt.Errorf("unable to get PagerDuty secret %s in %s: %w", secretName, namespace, err)
 	}
 
-	if pagerDutySecret.Data == nil {
-		return fmt.Errorf("no data in PagerDuty secret %s in %s", secretName, namespace)
-	}
-
-	_, exists := pagerDutySecret.Data[pagerDutyKey]
-	if !exists {
-		return fmt.Errorf("entry PAGERDUTY_KEY is missing from PagerDuty secret %s in %s", secretName, namespace)
+	if len(pagerDutySecret.Data[pagerDutyKey]) == 0 {
+		return &secretNotConfiguredError{
+			reason: fmt.Sprintf("entry %s is missing from PagerDuty secret %s in %s", pagerDutyKey, secretName, namespace),
+		}
 	}
 
 	return nil
@@ -325,19 +493,133 @@ func (r *MonitoringReconciler) getDeadMansSnitchURL(ctx context.Context, secretN
 		Name:      secretName,
 		Namespace: namespace,
 	}, deadMansSnitchSecret); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", &secretNotConfiguredError{
+				reason: fmt.Sprintf("DeadMan's Snitch secret %s not found in %s", secretName, namespace),
+			}
+		}
 		return "", fmt.Errorf("unable to get DeadMan's Snitch secret %s in %s: %w", secretName, namespace, err)
 	}
 
-	if deadMansSnitchSecret.Data == nil {
-		return "", fmt.Errorf("no data in DeadMansSnitch secret %s in %s", secretName, namespace)
+	deadMansSnitchURL := deadMansSnitchSecret.Data[snitchURLKey]
+	if len(deadMansSnitchURL) == 0 {
+		return "", &secretNotConfiguredError{
+			reason: fmt.Sprintf("entry %s is missing from DeadMan's Snitch secret %s in %s", snitchURLKey, secretName, namespace),
+		}
 	}
 
-	deadMansSnitchURL, exists := deadMansSnitchSecret.Data[snitchURLKey]
-	if !exists {
-		return "", fmt.Errorf("entry SNITCH_URL is missing from DeadMan's Snitch secret %s in %s", secretName, namespace)
+	return string(deadMansSnitchURL), nil
+}
+
+// isReferencedSecret tells whether the object is one of the Secrets the
+// alert receivers or the remote write of the Monitoring CR are configured from.
+func isReferencedSecret(m *addonv1alpha1.Monitoring, object client.Object) bool {
+	if object.GetNamespace() != m.Namespace {
+		return false
 	}
 
-	return string(deadMansSnitchURL), nil
+	switch object.GetName() {
+	case common.GlobalConfig.PagerDutySecretName, common.GlobalConfig.DeadMansSnitchSecretName:
+		return true
+	}
+
+	for _, name := range getReceiverSecretNames(m) {
+		if object.GetName() == name {
+			return true
+		}
+	}
+
+	for _, selector := range getRemoteWriteSecretKeySelectors(m.Spec.RemoteWrite) {
+		if object.GetName() == selector.Name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getDeadMansSnitchRouteCondition reports whether the Dead Man's Snitch
+// receiver is part of the configuration loaded by the addon Alertmanager. The
+// prometheus-operator only renders the receivers of the AlertmanagerConfigs
+// selected by the Alertmanager, prefixed by their namespace and name.
+func (r *MonitoringReconciler) getDeadMansSnitchRouteCondition(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring,
+	receivers *alertReceivers) (metav1.Condition, error) {
+
+	if reason, ok := receivers.notConfigured[deadMansSnitchReceiverName]; ok {
+		return common.NewCondition(
+			DeadMansSnitchRouteLoadedCondition,
+			metav1.ConditionFalse,
+			receiverNotConfiguredReason,
+			reason), nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      fmt.Sprintf(alertManagerGeneratedConfigSecretFormat, alertManagerName),
+		Namespace: m.Namespace,
+	}, secret); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return common.NewCondition(
+				DeadMansSnitchRouteLoadedCondition,
+				metav1.ConditionFalse,
+				"ConfigNotGenerated",
+				"The Alertmanager configuration has not been generated yet"), nil
+		}
+		return metav1.Condition{}, fmt.Errorf("unable to get the generated Alertmanager configuration: %w", err)
+	}
+
+	raw, err := getAlertManagerGeneratedConfig(secret)
+	if err != nil {
+		return metav1.Condition{}, err
+	}
+
+	config := struct {
+		Receivers []struct {
+			Name string `json:"name"`
+		} `json:"receivers"`
+	}{}
+	if err := yaml.Unmarshal(raw, &config); err != nil {
+		return metav1.Condition{}, fmt.Errorf("unable to parse the generated Alertmanager configuration: %w", err)
+	}
+
+	receiver := fmt.Sprintf("%s/%s/%s", m.Namespace, alertManagerConfigName, deadMansSnitchReceiverName)
+	for _, loaded := range config.Receivers {
+		if loaded.Name == receiver {
+			return common.NewCondition(
+				DeadMansSnitchRouteLoadedCondition,
+				metav1.ConditionTrue,
+				"RouteLoaded",
+				"The Dead Man's Snitch route is loaded in the Alertmanager"), nil
+		}
+	}
+
+	return common.NewCondition(
+		DeadMansSnitchRouteLoadedCondition,
+		metav1.ConditionFalse,
+		"RouteNotLoaded",
+		fmt.Sprintf("The receiver %s is not part of the Alertmanager configuration", receiver)), nil
+}
+
+func getAlertManagerGeneratedConfig(secret *corev1.Secret) ([]byte, error) {
+	if compressed, ok := secret.Data[alertManagerGeneratedConfigGzipKey]; ok {
+		reader, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return nil, fmt.Errorf("unable to decompress the generated Alertmanager configuration: %w", err)
+		}
+		defer reader.Close()
+
+		return io.ReadAll(reader)
+	}
+
+	return secret.Data[alertManagerGeneratedConfigKey], nil
+}
+
+func getAlertManagerConfigLabels() map[string]string {
+	return map[string]string{
+		"app": alertManagerName,
+	}
 }
 
 func convertToApiExtV1JSON(value interface{}) (apiextensionsv1.JSON, error) {
@@ -7,6 +7,7 @@ import (
 	promv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -53,6 +54,38 @@ var _ = Describe("AlertManager", Ordered, func() {
 				Namespace: m.Namespace,
 			}, &am)
 			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(*am.Spec.Replicas).To(Equal(int32(3)))
+			Expect(am.Spec.Retention).To(Equal("120h"))
+			Expect(am.Spec.Storage).To(BeNil())
+		})
+
+		It("should size AlertManager from the MonitoringSpec", func() {
+			replicas := int32(1)
+			m := m.DeepCopy()
+			m.Spec.Alertmanager = addonv1alpha1.MonitoringAlertmanagerSpec{
+				Replicas:      &replicas,
+				RetentionTime: "72h",
+				VolumeClaimTemplate: &addonv1alpha1.MonitoringVolumeClaimTemplate{
+					Size: resource.MustParse("1Gi"),
+				},
+			}
+
+			err := r.reconcileAlertManager(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = r.Get(context.TODO(), types.NamespacedName{
+				Name:      "gpuaddon-alertmanager",
+				Namespace: m.Namespace,
+			}, &am)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(*am.Spec.Replicas).To(Equal(int32(1)))
+			Expect(am.Spec.Retention).To(Equal("72h"))
+			Expect(am.Spec.Storage).ToNot(BeNil())
+			pvc := am.Spec.Storage.VolumeClaimTemplate.Spec
+			Expect(pvc.StorageClassName).To(BeNil())
+			Expect(pvc.Resources.Requests.Storage().String()).To(Equal("1Gi"))
 		})
 	})
 
@@ -141,14 +174,47 @@ var _ = Describe("AlertManagerConfig", Ordered, func() {
 
 			r.Client = c
 
-			err := r.reconcileAlertManagerConfig(context.TODO(), m)
+			receivers, err := r.reconcileAlertManagerConfig(context.TODO(), m)
 			Expect(err).ShouldNot(HaveOccurred())
+			Expect(receivers.notConfigured).To(BeEmpty())
 
 			err = c.Get(context.TODO(), types.NamespacedName{
 				Name:      alertManagerConfigName,
 				Namespace: m.Namespace,
 			}, &amc)
 			Expect(err).ShouldNot(HaveOccurred())
+			Expect(amc.Spec.Route.Routes).To(HaveLen(2))
+			Expect(amc.Spec.Receivers).To(HaveLen(3))
+		})
+
+		It("should omit the receivers whose Secret is missing or incomplete", func() {
+			incompletePagerDutySecret := pagerDutySecret.DeepCopy()
+			incompletePagerDutySecret.Data = map[string][]byte{}
+
+			c := fake.
+				NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(incompletePagerDutySecret).
+				Build()
+
+			r.Client = c
+
+			receivers, err := r.reconcileAlertManagerConfig(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(receivers.notConfigured).To(HaveKeyWithValue(pagerDutyReceiverName, ContainSubstring(pagerDutyKey)))
+			Expect(receivers.notConfigured).To(HaveKeyWithValue(deadMansSnitchReceiverName, ContainSubstring("not found")))
+
+			amc := &promv1alpha1.AlertmanagerConfig{}
+			err = c.Get(context.TODO(), types.NamespacedName{
+				Name:      alertManagerConfigName,
+				Namespace: m.Namespace,
+			}, amc)
+			Expect(err).ShouldNot(HaveOccurred())
+			// The watchdog alert is still routed, to the null receiver.
+			Expect(amc.Spec.Route.Routes).To(HaveLen(1))
+			Expect(string(amc.Spec.Route.Routes[0].Raw)).To(ContainSubstring(`"receiver":"null"`))
+			Expect(amc.Spec.Receivers).To(HaveLen(1))
+			Expect(amc.Spec.Receivers[0].Name).To(Equal("null"))
 		})
 	})
 
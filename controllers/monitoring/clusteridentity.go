@@ -0,0 +1,84 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+
+	configv1 "github.com/openshift/api/config/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/version"
+)
+
+const (
+	// External labels of the addon Prometheus, attached to every alert it
+	// sends to identify the cluster it fired on.
+	clusterIDLabel          = "cluster_id"
+	infrastructureNameLabel = "infrastructure_name"
+	addonVersionLabel       = "addon_version"
+
+	clusterVersionName = "version"
+
+	infrastructureName = "cluster"
+)
+
+// getClusterIdentityLabels returns the labels identifying the cluster and the
+// addon version. Labels which cannot be looked up, e.g. outside of OpenShift,
+// are left out.
+func (r *MonitoringReconciler) getClusterIdentityLabels(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring) (map[string]string, error) {
+
+	labels := map[string]string{}
+
+	clusterVersion := &configv1.ClusterVersion{}
+	if err := r.Get(ctx, types.NamespacedName{Name: clusterVersionName}, clusterVersion); err != nil {
+		if !k8serrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return nil, fmt.Errorf("unable to get ClusterVersion %s: %w", clusterVersionName, err)
+		}
+	} else if clusterVersion.Spec.ClusterID != "" {
+		labels[clusterIDLabel] = string(clusterVersion.Spec.ClusterID)
+	}
+
+	infrastructure := &configv1.Infrastructure{}
+	if err := r.Get(ctx, types.NamespacedName{Name: infrastructureName}, infrastructure); err != nil {
+		if !k8serrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return nil, fmt.Errorf("unable to get Infrastructure %s: %w", infrastructureName, err)
+		}
+	} else if infrastructure.Status.InfrastructureName != "" {
+		labels[infrastructureNameLabel] = infrastructure.Status.InfrastructureName
+	}
+
+	addonVersion, err := r.getAddonVersion(ctx, m.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	labels[addonVersionLabel] = addonVersion
+
+	return labels, nil
+}
+
+// getAddonVersion returns the version label set on the GPUAddon CR when the
+// addon operator starts, falling back to the version of the running binary.
+func (r *MonitoringReconciler) getAddonVersion(ctx context.Context, namespace string) (string, error) {
+	gpuAddon := &addonv1alpha1.GPUAddon{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      common.GlobalConfig.AddonID,
+		Namespace: namespace,
+	}, gpuAddon); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return "", fmt.Errorf("unable to get GPUAddon %s in %s: %w", common.GlobalConfig.AddonID, namespace, err)
+		}
+		return version.Version(), nil
+	}
+
+	if addonVersion, ok := gpuAddon.Labels[fmt.Sprintf("%v-version", common.GlobalConfig.AddonID)]; ok && addonVersion != "" {
+		return addonVersion, nil
+	}
+
+	return version.Version(), nil
+}
@@ -0,0 +1,144 @@
+package monitoring
+
+import (
+	"fmt"
+
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+)
+
+const (
+	gpuHealthRuleGroupName = "nvidia-gpu-addon-gpu-health.rules"
+
+	// xidFallenOffBus is the XID reported by the driver when a GPU is no
+	// longer reachable on the PCI bus.
+	xidFallenOffBus = 79
+
+	// xidErrorWindow is how long an XID error is reported for. The DCGM
+	// exporter only exposes the last XID of a GPU, which stays set until
+	// the next one, so the alerts fire on its changes instead.
+	xidErrorWindow = "15m"
+
+	// gpuHealthBy aggregates the DCGM exporter series per physical GPU.
+	gpuHealthBy = "by (Hostname, gpu, UUID, modelName)"
+)
+
+// getGPUHealthRuleGroup generates the DCGM based GPU health alerts, using the
+// thresholds set in the MonitoringSpec. Critical alerts are paged through
+// PagerDuty, the others are only visible in the addon Alertmanager.
+func getGPUHealthRuleGroup(spec addonv1alpha1.MonitoringGPUHealthSpec) promv1.RuleGroup {
+	thermalThrottlingPercent := addonv1alpha1.MonitoringDefaultThermalThrottlingPercent
+	if spec.ThermalThrottlingPercent > 0 {
+		thermalThrottlingPercent = spec.ThermalThrottlingPercent
+	}
+
+	powerViolationPercent := addonv1alpha1.MonitoringDefaultPowerViolationPercent
+	if spec.PowerViolationPercent > 0 {
+		powerViolationPercent = spec.PowerViolationPercent
+	}
+
+	throttlingFor := addonv1alpha1.MonitoringDefaultThrottlingFor
+	if spec.ThrottlingFor != "" {
+		throttlingFor = spec.ThrottlingFor
+	}
+
+	eccDoubleBitErrors := addonv1alpha1.MonitoringDefaultEccDoubleBitErrors
+	if spec.EccDoubleBitErrors != nil {
+		eccDoubleBitErrors = *spec.EccDoubleBitErrors
+	}
+
+	return promv1.RuleGroup{
+		Name: gpuHealthRuleGroupName,
+		Rules: []promv1.Rule{
+			{
+				Alert: "NVIDIAGPUAddonGPUXIDError",
+				Expr: intstr.FromString(fmt.Sprintf(
+					"max %s (DCGM_FI_DEV_XID_ERRORS > 0 != %d and changes(DCGM_FI_DEV_XID_ERRORS[%s]) > 0)",
+					gpuHealthBy, xidFallenOffBus, xidErrorWindow)),
+				Labels: map[string]string{
+					"severity": "warning",
+				},
+				Annotations: map[string]string{
+					"summary": "GPU {{ $labels.gpu }} on {{ $labels.Hostname }} reported XID error {{ $value }}",
+					"message": "GPU {{ $labels.gpu }} ({{ $labels.UUID }}) on {{ $labels.Hostname }} reported XID error " +
+						"{{ $value }}, please check the NVIDIA XID documentation for the recommended action.",
+				},
+			},
+			{
+				Alert: "NVIDIAGPUAddonGPUFallenOffBus",
+				Expr: intstr.FromString(fmt.Sprintf(
+					"max %s (DCGM_FI_DEV_XID_ERRORS == %d and changes(DCGM_FI_DEV_XID_ERRORS[%s]) > 0)",
+					gpuHealthBy, xidFallenOffBus, xidErrorWindow)),
+				Labels: map[string]string{
+					"severity": "critical",
+				},
+				Annotations: map[string]string{
+					"summary": "GPU {{ $labels.gpu }} on {{ $labels.Hostname }} has fallen off the bus",
+					"message": "GPU {{ $labels.gpu }} ({{ $labels.UUID }}) on {{ $labels.Hostname }} is no longer " +
+						"reachable (XID 79), the node must be drained and the GPU reset or replaced.",
+				},
+			},
+			{
+				Alert: "NVIDIAGPUAddonGPUEccDoubleBitErrors",
+				Expr: intstr.FromString(fmt.Sprintf(
+					"max %s (increase(DCGM_FI_DEV_ECC_DBE_VOL_TOTAL[15m])) > %d",
+					gpuHealthBy, eccDoubleBitErrors)),
+				Labels: map[string]string{
+					"severity": "critical",
+				},
+				Annotations: map[string]string{
+					"summary": "GPU {{ $labels.gpu }} on {{ $labels.Hostname }} reported ECC double-bit errors",
+					"message": "GPU {{ $labels.gpu }} ({{ $labels.UUID }}) on {{ $labels.Hostname }} reported " +
+						"{{ $value }} uncorrectable ECC double-bit errors in the last 15 minutes.",
+				},
+			},
+			{
+				Alert: "NVIDIAGPUAddonGPURowRemapFailure",
+				Expr: intstr.FromString(fmt.Sprintf(
+					"max %s (DCGM_FI_DEV_ROW_REMAP_FAILURE) > 0",
+					gpuHealthBy)),
+				Labels: map[string]string{
+					"severity": "critical",
+				},
+				Annotations: map[string]string{
+					"summary": "GPU {{ $labels.gpu }} on {{ $labels.Hostname }} failed to remap memory rows",
+					"message": "GPU {{ $labels.gpu }} ({{ $labels.UUID }}) on {{ $labels.Hostname }} failed to remap " +
+						"memory rows and should be replaced.",
+				},
+			},
+			{
+				// The violation counters are reported in microseconds.
+				Alert: "NVIDIAGPUAddonGPUThermalThrottling",
+				Expr: intstr.FromString(fmt.Sprintf(
+					"max %s (rate(DCGM_FI_DEV_THERMAL_VIOLATION[5m])) / 1e6 * 100 > %d",
+					gpuHealthBy, thermalThrottlingPercent)),
+				For: throttlingFor,
+				Labels: map[string]string{
+					"severity": "warning",
+				},
+				Annotations: map[string]string{
+					"summary": "GPU {{ $labels.gpu }} on {{ $labels.Hostname }} is thermally throttled",
+					"message": fmt.Sprintf("GPU {{ $labels.gpu }} ({{ $labels.UUID }}) on {{ $labels.Hostname }} has been "+
+						"thermally throttled more than %d%% of the time for %s.", thermalThrottlingPercent, throttlingFor),
+				},
+			},
+			{
+				Alert: "NVIDIAGPUAddonGPUPowerViolation",
+				Expr: intstr.FromString(fmt.Sprintf(
+					"max %s (rate(DCGM_FI_DEV_POWER_VIOLATION[5m])) / 1e6 * 100 > %d",
+					gpuHealthBy, powerViolationPercent)),
+				For: throttlingFor,
+				Labels: map[string]string{
+					"severity": "warning",
+				},
+				Annotations: map[string]string{
+					"summary": "GPU {{ $labels.gpu }} on {{ $labels.Hostname }} is power capped",
+					"message": fmt.Sprintf("GPU {{ $labels.gpu }} ({{ $labels.UUID }}) on {{ $labels.Hostname }} has been "+
+						"power capped more than %d%% of the time for %s.", powerViolationPercent, throttlingFor),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,307 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+const (
+	IdleGPUsCondition = "IdleGPUs"
+
+	// idleGPURetryInterval is how often the analysis is retried when the
+	// Prometheus queries fail.
+	idleGPURetryInterval = 5 * time.Minute
+
+	// maxIdleGPUPodsReported bounds the size of the Monitoring status.
+	maxIdleGPUPodsReported = 100
+
+	idleGPUEventReason = "IdleGPU"
+)
+
+// The DCGM exporter labels the GPUs with the pod they are allocated to,
+// renamed as they clash with the labels of the exporter target.
+const (
+	dcgmPodNamespaceLabel = "exported_namespace"
+	dcgmPodNameLabel      = "exported_pod"
+)
+
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// getIdleGPUQuery returns the highest utilization of the GPUs of the pods
+// whose GPUs all stayed below the threshold over the window. The pods must
+// already have held their GPUs at the start of the window, so that recently
+// started pods are not reported.
+func getIdleGPUQuery(threshold int32, window model.Duration) string {
+	by := fmt.Sprintf("%s, %s", dcgmPodNamespaceLabel, dcgmPodNameLabel)
+	selector := fmt.Sprintf(`DCGM_FI_DEV_GPU_UTIL{%s!=""}`, dcgmPodNameLabel)
+
+	return fmt.Sprintf(
+		"max by (%s) (max_over_time(%s[%s])) < %d and on (%s) count by (%s) (%s offset %s)",
+		by, selector, window, threshold, by, by, selector, window)
+}
+
+// getGPUCountQuery returns the number of GPUs allocated to each pod.
+func getGPUCountQuery() string {
+	return fmt.Sprintf(`count by (%s, %s) (DCGM_FI_DEV_GPU_UTIL{%s!=""})`,
+		dcgmPodNamespaceLabel, dcgmPodNameLabel, dcgmPodNameLabel)
+}
+
+// reconcileIdleGPUs looks for the pods holding idle GPUs once per interval
+// and reports them in the Monitoring status, the metrics and optionally
+// Events on the pods. It returns the condition of the analysis and when it is
+// due again.
+func (r *MonitoringReconciler) reconcileIdleGPUs(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring) (metav1.Condition, time.Duration, error) {
+
+	logger := log.FromContext(ctx, "Reconcile Step", "Idle GPUs")
+
+	spec := m.Spec.IdleGPU
+	if !spec.Enabled {
+		IdleGPUs.Reset()
+		if err := r.patchIdleGPUStatus(ctx, m, nil); err != nil {
+			return metav1.Condition{}, 0, err
+		}
+		return common.NewCondition(
+			IdleGPUsCondition,
+			metav1.ConditionFalse,
+			"NotEnabled",
+			"The idle GPU detection is not enabled"), 0, nil
+	}
+
+	threshold, window, interval, err := getIdleGPUSettings(spec)
+	if err != nil {
+		return metav1.Condition{}, 0, err
+	}
+
+	last := m.Status.IdleGPUs
+	if last != nil && last.Threshold == threshold && last.Window == window.String() &&
+		time.Since(last.LastAnalysisTime.Time) < time.Duration(interval) {
+		// The metrics are lost on restart, unlike the status.
+		setIdleGPUMetrics(last)
+		return getIdleGPUsCondition(last),
+			time.Until(last.LastAnalysisTime.Add(time.Duration(interval))), nil
+	}
+
+	if r.Prometheus == nil {
+		return common.NewCondition(
+			IdleGPUsCondition,
+			metav1.ConditionFalse,
+			"QuerierNotConfigured",
+			"No Prometheus querier is configured to look for idle GPUs"), 0, nil
+	}
+
+	now := time.Now()
+	status, err := r.getIdleGPUStatus(ctx, m, threshold, window, now)
+	if err != nil {
+		IdleGPUAnalysisFailures.WithLabelValues().Inc()
+		logger.Info("Failed to query the GPU utilization, retrying later", "error", err.Error())
+		return common.NewCondition(
+			IdleGPUsCondition,
+			metav1.ConditionFalse,
+			"QueryFailed",
+			err.Error()), idleGPURetryInterval, nil
+	}
+
+	setIdleGPUMetrics(status)
+
+	if spec.PodEvents {
+		if err := r.recordIdleGPUEvents(ctx, status); err != nil {
+			return metav1.Condition{}, 0, err
+		}
+	}
+
+	if err := r.patchIdleGPUStatus(ctx, m, status); err != nil {
+		return metav1.Condition{}, 0, err
+	}
+
+	logger.Info("Idle GPU analysis completed successfully",
+		"idlePods", status.IdlePods,
+		"idleGPUs", status.IdleGPUs)
+
+	return getIdleGPUsCondition(status), time.Duration(interval), nil
+}
+
+// getIdleGPUStatus queries the idle GPUs and the GPUs allocated to the pods.
+func (r *MonitoringReconciler) getIdleGPUStatus(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring,
+	threshold int32,
+	window model.Duration,
+	now time.Time) (*addonv1alpha1.MonitoringIdleGPUStatus, error) {
+
+	idle, err := r.Prometheus.Query(ctx, m, getIdleGPUQuery(threshold, window), now)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := r.Prometheus.Query(ctx, m, getGPUCountQuery(), now)
+	if err != nil {
+		return nil, err
+	}
+
+	gpus := map[types.NamespacedName]int32{}
+	for _, sample := range counts {
+		gpus[getSamplePod(sample)] = int32(sample.Value)
+	}
+
+	pods := make([]addonv1alpha1.MonitoringIdleGPUPod, 0, len(idle))
+	idleGPUs := int32(0)
+	for _, sample := range idle {
+		pod := getSamplePod(sample)
+		// The GPUs released since the start of the window are not counted.
+		if gpus[pod] == 0 {
+			continue
+		}
+
+		pods = append(pods, addonv1alpha1.MonitoringIdleGPUPod{
+			Namespace:      pod.Namespace,
+			Name:           pod.Name,
+			GPUs:           gpus[pod],
+			MaxUtilization: strconv.FormatFloat(float64(sample.Value), 'f', -1, 64),
+		})
+		idleGPUs += gpus[pod]
+	}
+
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+
+	status := &addonv1alpha1.MonitoringIdleGPUStatus{
+		LastAnalysisTime: metav1.NewTime(now),
+		Threshold:        threshold,
+		Window:           window.String(),
+		IdlePods:         int32(len(pods)),
+		IdleGPUs:         idleGPUs,
+		Pods:             pods,
+	}
+	if len(status.Pods) > maxIdleGPUPodsReported {
+		status.Pods = status.Pods[:maxIdleGPUPodsReported]
+	}
+
+	return status, nil
+}
+
+// recordIdleGPUEvents records a Warning Event on the reported pods still
+// running.
+func (r *MonitoringReconciler) recordIdleGPUEvents(
+	ctx context.Context,
+	status *addonv1alpha1.MonitoringIdleGPUStatus) error {
+
+	for _, idle := range status.Pods {
+		pod := &corev1.Pod{}
+		err := r.Get(ctx, types.NamespacedName{
+			Name:      idle.Name,
+			Namespace: idle.Namespace,
+		}, pod)
+		if k8serrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("unable to get Pod %s in %s: %w", idle.Name, idle.Namespace, err)
+		}
+
+		r.Recorder.Eventf(pod, corev1.EventTypeWarning, idleGPUEventReason,
+			"The %d GPU(s) allocated to the pod stayed below %d%% utilization over the last %s (max %s%%)",
+			idle.GPUs, status.Threshold, status.Window, idle.MaxUtilization)
+	}
+
+	return nil
+}
+
+func (r *MonitoringReconciler) patchIdleGPUStatus(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring,
+	status *addonv1alpha1.MonitoringIdleGPUStatus) error {
+
+	if m.Status.IdleGPUs == nil && status == nil {
+		return nil
+	}
+
+	patch := client.MergeFrom(m.DeepCopy())
+	m.Status.IdleGPUs = status
+
+	if err := r.Status().Patch(ctx, m, patch); err != nil {
+		return fmt.Errorf("failed to patch the idle GPU status: %w", err)
+	}
+
+	return nil
+}
+
+func setIdleGPUMetrics(status *addonv1alpha1.MonitoringIdleGPUStatus) {
+	IdleGPUs.Reset()
+	for _, pod := range status.Pods {
+		IdleGPUs.WithLabelValues(pod.Namespace, pod.Name).Set(float64(pod.GPUs))
+	}
+}
+
+func getIdleGPUsCondition(status *addonv1alpha1.MonitoringIdleGPUStatus) metav1.Condition {
+	if status.IdlePods == 0 {
+		return common.NewCondition(
+			IdleGPUsCondition,
+			metav1.ConditionFalse,
+			"NoIdleGPUs",
+			fmt.Sprintf("No GPU stayed below %d%% utilization over the last %s", status.Threshold, status.Window))
+	}
+
+	return common.NewCondition(
+		IdleGPUsCondition,
+		metav1.ConditionTrue,
+		"IdleGPUsFound",
+		fmt.Sprintf("%d pod(s) hold %d GPU(s) which stayed below %d%% utilization over the last %s",
+			status.IdlePods, status.IdleGPUs, status.Threshold, status.Window))
+}
+
+func getIdleGPUSettings(spec addonv1alpha1.MonitoringIdleGPUSpec) (int32, model.Duration, model.Duration, error) {
+	threshold := addonv1alpha1.MonitoringDefaultIdleGPUThreshold
+	if spec.Threshold != 0 {
+		threshold = spec.Threshold
+	}
+
+	window := addonv1alpha1.MonitoringDefaultIdleGPUWindow
+	if spec.Window != "" {
+		window = spec.Window
+	}
+
+	interval := addonv1alpha1.MonitoringDefaultIdleGPUInterval
+	if spec.Interval != "" {
+		interval = spec.Interval
+	}
+
+	windowDuration, err := model.ParseDuration(window)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid idle GPU window %q: %w", window, err)
+	}
+
+	intervalDuration, err := model.ParseDuration(interval)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid idle GPU interval %q: %w", interval, err)
+	}
+
+	return threshold, windowDuration, intervalDuration, nil
+}
+
+func getSamplePod(sample *model.Sample) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: string(sample.Metric[dcgmPodNamespaceLabel]),
+		Name:      string(sample.Metric[dcgmPodNameLabel]),
+	}
+}
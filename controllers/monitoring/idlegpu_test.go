@@ -0,0 +1,217 @@
+package monitoring
+
+import (
+	"context"
+	"net/http/httptest"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/prometheus"
+)
+
+var _ = Describe("Idle GPUs", func() {
+	common.ProcessConfig()
+
+	var server *httptest.Server
+
+	newMonitoring := func(spec addonv1alpha1.MonitoringIdleGPUSpec) *addonv1alpha1.Monitoring {
+		return &addonv1alpha1.Monitoring{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "test",
+			},
+			Spec: addonv1alpha1.MonitoringSpec{
+				IdleGPU: spec,
+			},
+		}
+	}
+
+	newReconciler := func(m *addonv1alpha1.Monitoring, pods ...*corev1.Pod) (*MonitoringReconciler, *record.FakeRecorder) {
+		r := newTestMonitoringReconciler(m)
+		for _, pod := range pods {
+			Expect(r.Create(context.TODO(), pod)).To(Succeed())
+		}
+
+		recorder := record.NewFakeRecorder(10)
+		r.Recorder = recorder
+		r.Prometheus = &testPrometheusQuerier{client: &prometheus.Client{Address: server.URL}}
+
+		return r, recorder
+	}
+
+	getStatus := func(r *MonitoringReconciler) *addonv1alpha1.MonitoringIdleGPUStatus {
+		m := &addonv1alpha1.Monitoring{}
+		Expect(r.Get(context.TODO(), types.NamespacedName{Name: "test", Namespace: "test"}, m)).To(Succeed())
+		return m.Status.IdleGPUs
+	}
+
+	getIdleGPUs := func(namespace, pod string) float64 {
+		metric := &dto.Metric{}
+		Expect(IdleGPUs.WithLabelValues(namespace, pod).Write(metric)).To(Succeed())
+		return metric.GetGauge().GetValue()
+	}
+
+	getFailures := func() float64 {
+		metric := &dto.Metric{}
+		Expect(IdleGPUAnalysisFailures.WithLabelValues().Write(metric)).To(Succeed())
+		return metric.GetCounter().GetValue()
+	}
+
+	BeforeEach(func() {
+		IdleGPUs.Reset()
+		server = newPrometheusStub(map[string]string{
+			"max_over_time": `[
+				{"metric":{"exported_namespace":"team-b","exported_pod":"trainer"},"value":[1660000000,"0"]},
+				{"metric":{"exported_namespace":"team-a","exported_pod":"notebook"},"value":[1660000000,"2.5"]},
+				{"metric":{"exported_namespace":"team-a","exported_pod":"released"},"value":[1660000000,"0"]}
+			]`,
+			getGPUCountQuery(): `[
+				{"metric":{"exported_namespace":"team-b","exported_pod":"trainer"},"value":[1660000000,"4"]},
+				{"metric":{"exported_namespace":"team-a","exported_pod":"notebook"},"value":[1660000000,"1"]},
+				{"metric":{"exported_namespace":"team-a","exported_pod":"busy"},"value":[1660000000,"2"]}
+			]`,
+		})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should only select the pods holding their GPUs over the whole window", func() {
+		query := getIdleGPUQuery(5, model.Duration(24*time.Hour))
+
+		Expect(query).To(ContainSubstring(`max_over_time(DCGM_FI_DEV_GPU_UTIL{exported_pod!=""}[1d])) < 5`))
+		Expect(query).To(ContainSubstring(`offset 1d`))
+	})
+
+	It("should not look for idle GPUs unless enabled", func() {
+		m := newMonitoring(addonv1alpha1.MonitoringIdleGPUSpec{})
+		r, _ := newReconciler(m)
+
+		condition, next, err := r.reconcileIdleGPUs(context.TODO(), m)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(next).To(BeZero())
+		Expect(condition.Reason).To(Equal("NotEnabled"))
+		Expect(getStatus(r)).To(BeNil())
+	})
+
+	It("should report the pods holding idle GPUs", func() {
+		m := newMonitoring(addonv1alpha1.MonitoringIdleGPUSpec{Enabled: true})
+		r, recorder := newReconciler(m)
+
+		condition, next, err := r.reconcileIdleGPUs(context.TODO(), m)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(next).To(Equal(time.Hour))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("IdleGPUsFound"))
+
+		status := getStatus(r)
+		Expect(status).ToNot(BeNil())
+		Expect(status.Threshold).To(Equal(int32(5)))
+		Expect(status.Window).To(Equal("1d"))
+		Expect(status.IdlePods).To(Equal(int32(2)))
+		Expect(status.IdleGPUs).To(Equal(int32(5)))
+		Expect(status.Pods).To(Equal([]addonv1alpha1.MonitoringIdleGPUPod{
+			{Namespace: "team-a", Name: "notebook", GPUs: 1, MaxUtilization: "2.5"},
+			{Namespace: "team-b", Name: "trainer", GPUs: 4, MaxUtilization: "0"},
+		}))
+
+		Expect(getIdleGPUs("team-a", "notebook")).To(Equal(1.0))
+		Expect(getIdleGPUs("team-b", "trainer")).To(Equal(4.0))
+
+		// Events are not recorded by default.
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("should record an Event on the running pods holding idle GPUs", func() {
+		m := newMonitoring(addonv1alpha1.MonitoringIdleGPUSpec{
+			Enabled:   true,
+			Threshold: 10,
+			PodEvents: true,
+		})
+		r, recorder := newReconciler(m, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "trainer",
+				Namespace: "team-b",
+			},
+		})
+
+		_, _, err := r.reconcileIdleGPUs(context.TODO(), m)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		// The notebook pod is gone.
+		Expect(recorder.Events).To(HaveLen(1))
+		Expect(<-recorder.Events).To(Equal(
+			"Warning IdleGPU The 4 GPU(s) allocated to the pod stayed below 10% utilization over the last 1d (max 0%)"))
+	})
+
+	It("should not analyze again before the interval elapsed", func() {
+		m := newMonitoring(addonv1alpha1.MonitoringIdleGPUSpec{Enabled: true})
+		r, _ := newReconciler(m)
+
+		_, _, err := r.reconcileIdleGPUs(context.TODO(), m)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		// Queries would fail from now on.
+		server.Close()
+		IdleGPUs.Reset()
+
+		condition, next, err := r.reconcileIdleGPUs(context.TODO(), m)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(condition.Reason).To(Equal("IdleGPUsFound"))
+		Expect(next).To(BeNumerically("<=", time.Hour))
+		Expect(next).To(BeNumerically(">", 59*time.Minute))
+
+		// The metrics are restored from the status.
+		Expect(getIdleGPUs("team-b", "trainer")).To(Equal(4.0))
+
+		// A new threshold is analyzed right away.
+		m.Spec.IdleGPU.Threshold = 20
+		condition, next, err = r.reconcileIdleGPUs(context.TODO(), m)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(condition.Reason).To(Equal("QueryFailed"))
+		Expect(next).To(Equal(idleGPURetryInterval))
+	})
+
+	It("should retry when Prometheus cannot be queried", func() {
+		server.Close()
+		failures := getFailures()
+
+		m := newMonitoring(addonv1alpha1.MonitoringIdleGPUSpec{Enabled: true})
+		r, _ := newReconciler(m)
+
+		condition, next, err := r.reconcileIdleGPUs(context.TODO(), m)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("QueryFailed"))
+		Expect(next).To(Equal(idleGPURetryInterval))
+		Expect(getFailures()).To(Equal(failures + 1))
+		Expect(getStatus(r)).To(BeNil())
+	})
+
+	It("should clear the report once disabled", func() {
+		m := newMonitoring(addonv1alpha1.MonitoringIdleGPUSpec{Enabled: true})
+		r, _ := newReconciler(m)
+
+		_, _, err := r.reconcileIdleGPUs(context.TODO(), m)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(getStatus(r)).ToNot(BeNil())
+
+		m.Spec.IdleGPU.Enabled = false
+		_, _, err = r.reconcileIdleGPUs(context.TODO(), m)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(getStatus(r)).To(BeNil())
+		Expect(getIdleGPUs("team-b", "trainer")).To(BeZero())
+	})
+})
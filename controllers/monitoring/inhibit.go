@@ -0,0 +1,67 @@
+package monitoring
+
+import (
+	promv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+)
+
+const (
+	uninstallingAlert = "NVIDIAGPUAddonUninstalling"
+)
+
+var (
+	// Alerts raised while the GPU operator, and so its operands exporting
+	// the per-GPU metrics, is not available.
+	gpuOperatorUnavailableAlerts = []string{
+		"NVIDIAGPUAddonGPUOperatorSubscriptionInstallationPending",
+		"NVIDIAGPUAddonClusterPolicyNotReady",
+	}
+)
+
+// getInhibitRules generates the inhibit rules of the addon AlertmanagerConfig,
+// so that a single failure does not fire a cascade of alerts. The
+// prometheus-operator restricts them to the alerts of the addon namespace.
+func getInhibitRules(m *addonv1alpha1.Monitoring) []promv1alpha1.InhibitRule {
+	gpuHealthAlerts := []string{}
+	for _, rule := range getGPUHealthRuleGroup(m.Spec.GPUHealth).Rules {
+		gpuHealthAlerts = append(gpuHealthAlerts, rule.Alert)
+	}
+
+	return []promv1alpha1.InhibitRule{
+		{
+			SourceMatch: []promv1alpha1.Matcher{
+				{
+					Name:      "alertname",
+					Value:     getRegexMatcher(gpuOperatorUnavailableAlerts),
+					MatchType: promv1alpha1.MatchRegexp,
+				},
+			},
+			TargetMatch: []promv1alpha1.Matcher{
+				{
+					Name:      "alertname",
+					Value:     getRegexMatcher(gpuHealthAlerts),
+					MatchType: promv1alpha1.MatchRegexp,
+				},
+			},
+		},
+		{
+			// The watchdog is not inhibited, Dead Man's Snitch would
+			// otherwise raise an incident before the addon is gone.
+			SourceMatch: []promv1alpha1.Matcher{
+				{
+					Name:      "alertname",
+					Value:     uninstallingAlert,
+					MatchType: promv1alpha1.MatchEqual,
+				},
+			},
+			TargetMatch: []promv1alpha1.Matcher{
+				{
+					Name:      "alertname",
+					Value:     getRegexMatcher([]string{uninstallingAlert, deadMansSnitchReceiverName}),
+					MatchType: promv1alpha1.MatchNotRegexp,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,162 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+
+	promv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+var _ = Describe("Alert inhibition and grouping", func() {
+	common.ProcessConfig()
+
+	pagerDutySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.GlobalConfig.PagerDutySecretName,
+			Namespace: "test",
+		},
+		Data: map[string][]byte{
+			pagerDutyKey: []byte("some-service-key"),
+		},
+	}
+
+	getAlertManagerConfig := func(m *addonv1alpha1.Monitoring) *promv1alpha1.AlertmanagerConfig {
+		r := newTestMonitoringReconciler(m, pagerDutySecret)
+
+		_, err := r.reconcileAlertManagerConfig(context.TODO(), m)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		amc := &promv1alpha1.AlertmanagerConfig{}
+		err = r.Get(context.TODO(), types.NamespacedName{
+			Name:      alertManagerConfigName,
+			Namespace: m.Namespace,
+		}, amc)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		return amc
+	}
+
+	getPagerDutyRoute := func(amc *promv1alpha1.AlertmanagerConfig) promv1alpha1.Route {
+		for _, raw := range amc.Spec.Route.Routes {
+			route := promv1alpha1.Route{}
+			Expect(json.Unmarshal(raw.Raw, &route)).To(Succeed())
+			if route.Receiver == pagerDutyReceiverName {
+				return route
+			}
+		}
+		Fail("no PagerDuty route")
+		return promv1alpha1.Route{}
+	}
+
+	It("should inhibit the GPU health alerts while the GPU operator is unavailable", func() {
+		rules := getInhibitRules(&addonv1alpha1.Monitoring{})
+		Expect(rules).To(HaveLen(2))
+
+		Expect(rules[0].SourceMatch[0].Value).To(Equal(
+			"^NVIDIAGPUAddonGPUOperatorSubscriptionInstallationPending$|^NVIDIAGPUAddonClusterPolicyNotReady$"))
+		for _, alert := range []string{
+			"NVIDIAGPUAddonGPUXIDError",
+			"NVIDIAGPUAddonGPUFallenOffBus",
+			"NVIDIAGPUAddonGPUThermalThrottling",
+		} {
+			Expect(rules[0].TargetMatch[0].Value).To(ContainSubstring("^" + alert + "$"))
+		}
+		Expect(rules[0].TargetMatch[0].Value).ToNot(ContainSubstring("SubscriptionInstallationPending"))
+	})
+
+	It("should inhibit all the alerts but the watchdog during uninstallation", func() {
+		catalog, err := loadAlertCatalog()
+		Expect(err).ShouldNot(HaveOccurred())
+
+		alerts := []string{}
+		for _, group := range catalog.Groups {
+			for _, rule := range group.Rules {
+				alerts = append(alerts, rule.Alert)
+			}
+		}
+		Expect(alerts).To(ContainElement(uninstallingAlert))
+
+		rule := getInhibitRules(&addonv1alpha1.Monitoring{})[1]
+		Expect(rule.SourceMatch).To(ConsistOf(promv1alpha1.Matcher{
+			Name:      "alertname",
+			Value:     uninstallingAlert,
+			MatchType: promv1alpha1.MatchEqual,
+		}))
+		Expect(rule.TargetMatch).To(ConsistOf(promv1alpha1.Matcher{
+			Name:      "alertname",
+			Value:     "^NVIDIAGPUAddonUninstalling$|^DeadMansSnitch$",
+			MatchType: promv1alpha1.MatchNotRegexp,
+		}))
+	})
+
+	It("should set the inhibit rules and the default PagerDuty grouping", func() {
+		amc := getAlertManagerConfig(&addonv1alpha1.Monitoring{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "test",
+			},
+		})
+		Expect(amc.Spec.InhibitRules).To(HaveLen(2))
+
+		route := getPagerDutyRoute(amc)
+		Expect(route.GroupBy).To(Equal([]string{"alertname"}))
+		Expect(route.GroupWait).To(Equal(addonv1alpha1.MonitoringDefaultPagerDutyGroupWait))
+		Expect(route.GroupInterval).To(Equal(addonv1alpha1.MonitoringDefaultPagerDutyGroupInterval))
+		Expect(route.RepeatInterval).To(Equal(addonv1alpha1.MonitoringDefaultPagerDutyRepeatInterval))
+	})
+
+	It("should identify the cluster in the PagerDuty incidents", func() {
+		amc := getAlertManagerConfig(&addonv1alpha1.Monitoring{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "test",
+			},
+		})
+
+		var pagerDuty *promv1alpha1.PagerDutyConfig
+		for _, receiver := range amc.Spec.Receivers {
+			if receiver.Name == pagerDutyReceiverName {
+				pagerDuty = &receiver.PagerDutyConfigs[0]
+			}
+		}
+		Expect(pagerDuty).ToNot(BeNil())
+		Expect(pagerDuty.Description).To(ContainSubstring(".CommonLabels.infrastructure_name"))
+		Expect(pagerDuty.Details).To(ContainElements(
+			promv1alpha1.KeyValue{Key: clusterIDLabel, Value: "{{ .CommonLabels.cluster_id }}"},
+			promv1alpha1.KeyValue{Key: infrastructureNameLabel, Value: "{{ .CommonLabels.infrastructure_name }}"},
+			promv1alpha1.KeyValue{Key: addonVersionLabel, Value: "{{ .CommonLabels.addon_version }}"},
+		))
+	})
+
+	It("should group the PagerDuty notifications as set in the spec", func() {
+		amc := getAlertManagerConfig(&addonv1alpha1.Monitoring{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "test",
+			},
+			Spec: addonv1alpha1.MonitoringSpec{
+				PagerDuty: addonv1alpha1.MonitoringPagerDutySpec{
+					GroupBy:        []string{"alertname", "Hostname"},
+					GroupWait:      "1m",
+					GroupInterval:  "10m",
+					RepeatInterval: "4h",
+				},
+			},
+		})
+
+		route := getPagerDutyRoute(amc)
+		Expect(route.GroupBy).To(Equal([]string{"alertname", "Hostname"}))
+		Expect(route.GroupWait).To(Equal("1m"))
+		Expect(route.GroupInterval).To(Equal("10m"))
+		Expect(route.RepeatInterval).To(Equal("4h"))
+	})
+})
@@ -0,0 +1,122 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+const (
+	// kubeRBACProxyDefaultOpenShiftVersion is the OpenShift version whose
+	// kube-rbac-proxy image is used when the cluster version is unknown.
+	kubeRBACProxyDefaultOpenShiftVersion = "4.10"
+)
+
+var (
+	kubeRBACProxyDefaultResources = corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			"cpu":    resource.MustParse("500m"),
+			"memory": resource.MustParse("128Mi"),
+		},
+		Requests: corev1.ResourceList{
+			"cpu":    resource.MustParse("5m"),
+			"memory": resource.MustParse("64Mi"),
+		},
+	}
+)
+
+// getOpenShiftKubeRBACProxyImages returns the kube-rbac-proxy image shipped
+// with each OpenShift version, as configured in the operator Deployment.
+func getOpenShiftKubeRBACProxyImages() map[string]string {
+	return map[string]string{
+		"4.9":  common.GlobalConfig.KubeRBACProxyImage4_9,
+		"4.10": common.GlobalConfig.KubeRBACProxyImage4_10,
+		"4.11": common.GlobalConfig.KubeRBACProxyImage4_11,
+	}
+}
+
+// getKubeRBACProxyImage returns the kube-rbac-proxy image of the OpenShift
+// version of the cluster, or the one of the default version when the cluster
+// version is unknown or has no image.
+func (r *MonitoringReconciler) getKubeRBACProxyImage(ctx context.Context) (string, error) {
+	logger := log.FromContext(ctx, "Reconcile Step", "Prometheus CR")
+
+	images := getOpenShiftKubeRBACProxyImages()
+
+	ocpVersion, err := common.GetOpenShiftVersion(r.Client)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) && !meta.IsNoMatchError(err) &&
+			!errors.Is(err, common.ErrNoCompletedClusterVersion) {
+			return "", fmt.Errorf("unable to get the OpenShift version: %w", err)
+		}
+		// Unknown outside of OpenShift or until the cluster is installed.
+		logger.Info("OpenShift version unknown, using the kube-rbac-proxy image of the default version",
+			"reason", err.Error(),
+			"defaultVersion", kubeRBACProxyDefaultOpenShiftVersion)
+		ocpVersion = kubeRBACProxyDefaultOpenShiftVersion
+	}
+
+	image := images[ocpVersion]
+	if image == "" && ocpVersion != kubeRBACProxyDefaultOpenShiftVersion {
+		logger.Info("No kube-rbac-proxy image known for the OpenShift version, using the one of the default version",
+			"ocpVersion", ocpVersion,
+			"defaultVersion", kubeRBACProxyDefaultOpenShiftVersion)
+		ocpVersion = kubeRBACProxyDefaultOpenShiftVersion
+		image = images[ocpVersion]
+	}
+	if image == "" {
+		return "", fmt.Errorf("no kube-rbac-proxy image configured for OpenShift %s, RELATED_IMAGE_KUBE_RBAC_PROXY_%s must be set",
+			ocpVersion, strings.ReplaceAll(ocpVersion, ".", "_"))
+	}
+
+	return image, nil
+}
+
+// getKubeRBACProxyContainer returns the sidecar exposing the addon Prometheus,
+// which only listens on localhost, to the authorized clients.
+func getKubeRBACProxyContainer(image string, spec addonv1alpha1.MonitoringKubeRBACProxySpec) corev1.Container {
+	resources := *kubeRBACProxyDefaultResources.DeepCopy()
+	if spec.Resources != nil {
+		resources = *spec.Resources.DeepCopy()
+	}
+
+	return corev1.Container{
+		Image: image,
+		Name:  "kube-rbac-proxy",
+		Args: []string{
+			fmt.Sprintf("--secure-listen-address=0.0.0.0:%d", kubeRBACProxyPort),
+			"--upstream=http://127.0.0.1:9090/",
+			"--logtostderr=true",
+			fmt.Sprintf("--v=%d", spec.LogLevel),
+			"--tls-cert-file=/etc/tls-secret/tls.crt",
+			"--tls-private-key-file=/etc/tls-secret/tls.key",
+			"--client-ca-file=/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt",
+			"--config-file=/etc/kube-rbac-config/config-file.json",
+		},
+		Ports: []corev1.ContainerPort{{
+			Name:          "https",
+			ContainerPort: kubeRBACProxyPort,
+		}},
+		Resources: resources,
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "serving-cert",
+				MountPath: "/etc/tls-secret",
+			},
+			{
+				Name:      "kube-rbac-config",
+				MountPath: "/etc/kube-rbac-config",
+			},
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package monitoring
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	RemoteWriteUp = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "nvidia_gpuaddon_monitoring_remote_write_up",
+			Help: "Reports whether the addon Prometheus sends the samples to its remote write endpoint without failures",
+		},
+	)
+
+	RemoteWriteFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "nvidia_gpuaddon_monitoring_remote_write_failures_total",
+			Help: "Number of remote write status checks which found the remote write of the addon Prometheus failing",
+		},
+		[]string{"reason"},
+	)
+
+	IdleGPUs = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "nvidia_gpuaddon_idle_gpus",
+			Help: "Number of GPUs held by a pod which stayed below the utilization threshold over the idle GPU window",
+		},
+		[]string{"workload_namespace", "workload_pod"},
+	)
+
+	IdleGPUAnalysisFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "nvidia_gpuaddon_idle_gpu_analysis_failures_total",
+			Help: "Number of idle GPU analyses which could not query the addon Prometheus",
+		},
+		[]string{},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		RemoteWriteUp,
+		RemoteWriteFailures,
+		IdleGPUs,
+		IdleGPUAnalysisFailures,
+	)
+}
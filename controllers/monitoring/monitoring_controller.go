@@ -19,18 +19,39 @@ package monitoring
 import (
 	"context"
 	"fmt"
+	"time"
 
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	promv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/health"
+)
+
+const (
+	DeadMansSnitchRouteLoadedCondition = "DeadMansSnitchRouteLoaded"
+
+	// routeCheckInterval is how often the loaded Alertmanager configuration is
+	// checked until the Dead Man's Snitch route shows up.
+	routeCheckInterval = time.Minute
+
+	// gpuOperatorDiscoveryInterval is how often the ClusterPolicy is checked
+	// until it reports the GPU operator namespace.
+	gpuOperatorDiscoveryInterval = time.Minute
 )
 
 // MonitoringReconciler reconciles the monitoring stack used by the add-on operator.
@@ -38,16 +59,30 @@ type MonitoringReconciler struct {
 	client.Client
 
 	Scheme *runtime.Scheme
+
+	// Prometheus queries the metrics collected for the showback report. The
+	// report is not generated when not set.
+	Prometheus PrometheusQuerier
+
+	// Recorder records the Events on the pods holding idle GPUs.
+	Recorder record.EventRecorder
+
+	// ReconcileTracker reports the outcome of the reconciliations in the
+	// readiness checks and metrics. Not tracked when not set.
+	ReconcileTracker *health.ReconcileTracker
 }
 
 //+kubebuilder:rbac:groups=nvidia.addons.rh-ecosystem-edge.io,namespace=system,resources=monitorings,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=nvidia.addons.rh-ecosystem-edge.io,namespace=system,resources=monitorings/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=nvidia.addons.rh-ecosystem-edge.io,namespace=system,resources=monitorings/finalizers,verbs=update
 //+kubebuilder:rbac:groups=monitoring.coreos.com,namespace=system,resources={alertmanagers,prometheuses,alertmanagerconfigs},verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=monitoring.coreos.com,namespace=system,resources=prometheusrules,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=monitoring.coreos.com,namespace=system,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=monitoring.coreos.com,namespace=system,resources=podmonitors,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=monitoring.coreos.com,namespace=system,resources=servicemonitors,verbs=get;list;watch;update;patch;create;delete
-//+kubebuilder:rbac:groups="",namespace=system,resources=secrets,verbs=create;get;list;watch;update
+//+kubebuilder:rbac:groups="",namespace=system,resources=secrets,verbs=create;get;list;watch;update;patch;delete
+//+kubebuilder:rbac:groups=nvidia.com,resources=clusterpolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apps,namespace=system,resources=statefulsets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions;infrastructures,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -73,48 +108,213 @@ func (r *MonitoringReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	if !monitoring.ObjectMeta.DeletionTimestamp.IsZero() {
-		if err := r.removeOwnedResources(ctx, &monitoring); err != nil {
-			return ctrl.Result{}, err
+		logger.Info(fmt.Sprintf("Monitoring CR %v/%v marked for deletion", req.Namespace, req.Name))
+		if !controllerutil.ContainsFinalizer(&monitoring, common.GlobalConfig.AddonID) {
+			return ctrl.Result{}, nil
+		}
+
+		resource, err := r.removeOwnedResources(ctx, &monitoring)
+		if err != nil {
+			return ctrl.Result{}, r.patchStatus(ctx, &monitoring, []metav1.Condition{
+				getUninstallingCondition(resource, err),
+				getAvailableConditionUninstalling(),
+			}, nil, err)
+		}
+
+		controllerutil.RemoveFinalizer(&monitoring, common.GlobalConfig.AddonID)
+
+		if err := r.Update(ctx, &monitoring); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to remove finalizer: %w", err)
 		}
 		return ctrl.Result{}, nil
 	}
 
-	if err := r.reconcilePrometheusKubeRBACProxyConfigMap(ctx, &monitoring); err != nil {
-		logger.Error(err, "Reconcilation failed",
-			"resource", prometheusKubeRBACProxyConfigMapName,
-			"namespace", monitoring.Namespace)
+	if err := r.registerFinalizerIfNeeded(ctx, &monitoring); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	if err := r.reconcilePrometheusService(ctx, &monitoring); err != nil {
-		logger.Error(err, "Reconcilation failed",
-			"resource", prometheusServiceName,
-			"namespace", monitoring.Namespace)
+	gpuOperatorDiscovered := false
+	receivers := &alertReceivers{}
+
+	steps := []struct {
+		condition string
+		resource  string
+		reconcile func(context.Context, *addonv1alpha1.Monitoring) error
+		// statefulSet generated by the prometheus-operator, whose readiness
+		// is reported by the step condition.
+		statefulSet string
+		// delete removes the resource of a step of the Dedicated mode when
+		// the user-workload monitoring is used instead.
+		delete func(context.Context, *addonv1alpha1.Monitoring) error
+	}{
+		{
+			condition: KubeRBACProxyConfigCondition,
+			resource:  prometheusKubeRBACProxyConfigMapName,
+			reconcile: r.reconcilePrometheusKubeRBACProxyConfigMap,
+			delete:    r.deletePrometheusKubeRBACProxyConfigMap,
+		},
+		{
+			condition: PrometheusServiceCondition,
+			resource:  prometheusServiceName,
+			reconcile: r.reconcilePrometheusService,
+			delete:    r.deletePrometheusService,
+		},
+		{
+			condition:   PrometheusCondition,
+			resource:    prometheusName,
+			reconcile:   r.reconcilePrometheus,
+			statefulSet: fmt.Sprintf("prometheus-%s", prometheusName),
+			delete:      r.deletePrometheus,
+		},
+		{
+			condition: PrometheusRuleCondition,
+			resource:  prometheusRuleName,
+			reconcile: r.reconcilePrometheusRule,
+		},
+		{
+			condition: ServiceMonitorsCondition,
+			resource:  "ServiceMonitors",
+			reconcile: func(ctx context.Context, m *addonv1alpha1.Monitoring) error {
+				var err error
+				gpuOperatorDiscovered, err = r.reconcileServiceMonitors(ctx, m)
+				return err
+			},
+		},
+		{
+			condition:   AlertmanagerCondition,
+			resource:    alertManagerName,
+			reconcile:   r.reconcileAlertManager,
+			statefulSet: fmt.Sprintf("alertmanager-%s", alertManagerName),
+			delete:      r.deleteAlertManager,
+		},
+		{
+			condition: AlertmanagerConfigCondition,
+			resource:  alertManagerConfigName,
+			reconcile: func(ctx context.Context, m *addonv1alpha1.Monitoring) error {
+				var err error
+				receivers, err = r.reconcileAlertManagerConfig(ctx, m)
+				return err
+			},
+		},
+	}
+
+	conditions := []metav1.Condition{}
+	staleConditions := []string{}
+
+	for _, step := range steps {
+		if isUserWorkloadMode(&monitoring) && step.delete != nil {
+			if err := step.delete(ctx, &monitoring); err != nil {
+				return ctrl.Result{}, err
+			}
+			staleConditions = append(staleConditions, step.condition)
+			continue
+		}
+
+		if err := step.reconcile(ctx, &monitoring); err != nil {
+			logger.Error(err, "Reconcilation failed",
+				"resource", step.resource,
+				"namespace", monitoring.Namespace)
+
+			conditions = append(conditions, getStepConditionFailed(step.condition, err))
+			conditions = append(conditions, getDegradedConditionFailed(step.condition, err))
+			conditions = append(conditions, common.NewCondition(
+				AvailableCondition,
+				metav1.ConditionFalse,
+				"ReconcileFailed",
+				fmt.Sprintf("%s failed to reconcile", step.condition)))
+
+			return ctrl.Result{}, r.patchStatus(ctx, &monitoring, conditions, staleConditions, err)
+		}
+
+		condition := getStepConditionReconciled(step.condition)
+		if step.statefulSet != "" {
+			var err error
+			condition, err = r.getStatefulSetCondition(ctx, step.condition, monitoring.Namespace, step.statefulSet)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	// The configuration loaded by the user-workload Alertmanager is not
+	// readable from the addon namespace.
+	if isUserWorkloadMode(&monitoring) {
+		staleConditions = append(staleConditions, DeadMansSnitchRouteLoadedCondition)
+	} else {
+		routeCondition, err := r.getDeadMansSnitchRouteCondition(ctx, &monitoring, receivers)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		conditions = append(conditions, routeCondition)
+	}
+
+	result := ctrl.Result{}
+	requeueAfter := func(d time.Duration) {
+		if result.RequeueAfter == 0 || d < result.RequeueAfter {
+			result.RequeueAfter = d
+		}
+	}
+
+	for _, condition := range conditions {
+		if condition.Status == metav1.ConditionTrue ||
+			condition.Reason == receiverNotConfiguredReason {
+			continue
+		}
+
+		logger.Info("Monitoring component not ready yet",
+			"condition", condition.Type,
+			"reason", condition.Reason)
+
+		if condition.Type == DeadMansSnitchRouteLoadedCondition {
+			requeueAfter(routeCheckInterval)
+		} else {
+			requeueAfter(readinessCheckInterval)
+		}
+	}
+
+	remoteWriteCondition, err := r.getRemoteWriteCondition(ctx, &monitoring)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
+	if monitoring.Spec.RemoteWrite != nil {
+		requeueAfter(remoteWriteCheckInterval)
+	}
 
-	if err := r.reconcilePrometheus(ctx, &monitoring); err != nil {
-		logger.Error(err, "Reconcilation failed",
-			"resource", prometheusName,
-			"namespace", monitoring.Namespace)
+	showbackCondition, next, err := r.reconcileShowbackReport(ctx, &monitoring)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
+	if next > 0 {
+		requeueAfter(next)
+	}
 
-	if err := r.reconcileAlertManager(ctx, &monitoring); err != nil {
-		logger.Error(err, "Reconcilation failed",
-			"resource", alertManagerName,
-			"namespace", monitoring.Namespace)
+	idleGPUsCondition, next, err := r.reconcileIdleGPUs(ctx, &monitoring)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
+	if next > 0 {
+		requeueAfter(next)
+	}
+
+	conditions = append(conditions,
+		getAvailableCondition(conditions),
+		getDegradedConditionSuccess(),
+		getReceiverNotConfiguredCondition(receivers.notConfigured),
+		remoteWriteCondition,
+		showbackCondition,
+		idleGPUsCondition)
 
-	if err := r.reconcileAlertManagerConfig(ctx, &monitoring); err != nil {
-		logger.Error(err, "Reconcilation failed",
-			"resource", alertManagerConfigName,
-			"namespace", monitoring.Namespace)
+	if err := r.patchStatus(ctx, &monitoring, conditions, staleConditions, nil); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	if !gpuOperatorDiscovered {
+		requeueAfter(gpuOperatorDiscoveryInterval)
+	}
+
+	return result, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -128,32 +328,60 @@ func (r *MonitoringReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&promv1.PrometheusRule{}).
 		Owns(&promv1.ServiceMonitor{}).
 		Owns(&promv1alpha1.AlertmanagerConfig{}).
-		Complete(r)
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.getMonitoringRequestsForSecret)).
+		Complete(r.ReconcileTracker.Track("monitoring", r))
 }
 
-func (r *MonitoringReconciler) removeOwnedResources(
-	ctx context.Context,
-	m *addonv1alpha1.Monitoring) error {
-
-	if err := r.deleteAlertManagerConfig(ctx, m); err != nil {
-		return err
+// getMonitoringRequestsForSecret reconciles the Monitoring CRs referencing
+// a Secret when it changes, so that alert receivers and the remote write are
+// configured without waiting for another event.
+func (r *MonitoringReconciler) getMonitoringRequestsForSecret(object client.Object) []reconcile.Request {
+	monitorings := &addonv1alpha1.MonitoringList{}
+	if err := r.List(context.TODO(), monitorings, client.InNamespace(object.GetNamespace())); err != nil {
+		log.Log.Error(err, "Failed to list Monitoring CRs", "namespace", object.GetNamespace())
+		return nil
 	}
 
-	if err := r.deleteAlertManager(ctx, m); err != nil {
-		return err
+	requests := []reconcile.Request{}
+	for i := range monitorings.Items {
+		m := &monitorings.Items[i]
+		if !isReferencedSecret(m, object) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      m.Name,
+				Namespace: m.Namespace,
+			},
+		})
 	}
 
-	if err := r.deletePrometheus(ctx, m); err != nil {
-		return err
-	}
+	return requests
+}
 
-	if err := r.deletePrometheusService(ctx, m); err != nil {
-		return err
+// getStorageSpec translates the volume claim template of a monitoring
+// component into its prometheus-operator storage, nil keeping the default
+// emptyDir volume.
+func getStorageSpec(t *addonv1alpha1.MonitoringVolumeClaimTemplate) *promv1.StorageSpec {
+	if t == nil {
+		return nil
 	}
 
-	if err := r.deletePrometheusKubeRBACProxyConfigMap(ctx, m); err != nil {
-		return err
+	return &promv1.StorageSpec{
+		VolumeClaimTemplate: promv1.EmbeddedPersistentVolumeClaim{
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{
+					corev1.ReadWriteOnce,
+				},
+				StorageClassName: t.StorageClassName,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: t.Size,
+					},
+				},
+			},
+		},
 	}
-
-	return nil
 }
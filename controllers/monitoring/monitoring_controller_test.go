@@ -1,14 +1,20 @@
 package monitoring
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"reflect"
 	"time"
 
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	configv1 "github.com/openshift/api/config/v1"
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	promv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -52,7 +58,15 @@ var _ = Describe("Monitoring Reconcile", Ordered, func() {
 				Namespace: "test",
 			},
 		}
-		r := newTestMonitoringReconciler(monitoring, pagerDutySecret, deadMansSnitchSecret)
+		clusterPolicy := &gpuv1.ClusterPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: common.GlobalConfig.ClusterPolicyName,
+			},
+			Status: gpuv1.ClusterPolicyStatus{
+				Namespace: "nvidia-gpu-operator",
+			},
+		}
+		r := newTestMonitoringReconciler(monitoring, pagerDutySecret, deadMansSnitchSecret, clusterPolicy)
 
 		It("should not throw an error", func() {
 			req := reconcile.Request{
@@ -61,9 +75,45 @@ var _ = Describe("Monitoring Reconcile", Ordered, func() {
 					Name:      monitoring.Name,
 				},
 			}
-			_, err := r.Reconcile(context.TODO(), req)
+			res, err := r.Reconcile(context.TODO(), req)
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(res.RequeueAfter).To(Equal(readinessCheckInterval))
+		})
 
+		It("should report the reconciled and not ready components", func() {
+			m := &addonv1alpha1.Monitoring{}
+			err := r.Client.Get(context.TODO(), types.NamespacedName{
+				Namespace: monitoring.Namespace,
+				Name:      monitoring.Name,
+			}, m)
 			Expect(err).ShouldNot(HaveOccurred())
+
+			for _, conditionType := range []string{
+				KubeRBACProxyConfigCondition,
+				PrometheusServiceCondition,
+				PrometheusRuleCondition,
+				ServiceMonitorsCondition,
+				AlertmanagerConfigCondition,
+			} {
+				Expect(common.ContainCondition(m.Status.Conditions,
+					conditionType, metav1.ConditionTrue)).To(BeTrue(), conditionType)
+			}
+
+			for _, conditionType := range []string{
+				PrometheusCondition,
+				AlertmanagerCondition,
+			} {
+				Expect(common.ContainCondition(m.Status.Conditions,
+					conditionType, metav1.ConditionFalse)).To(BeTrue(), conditionType)
+				Expect(meta.FindStatusCondition(m.Status.Conditions,
+					conditionType).Reason).To(Equal("StatefulSetNotFound"))
+			}
+
+			Expect(common.ContainCondition(m.Status.Conditions,
+				AvailableCondition, metav1.ConditionFalse)).To(BeTrue())
+			Expect(common.ContainCondition(m.Status.Conditions,
+				DegradedCondition, metav1.ConditionFalse)).To(BeTrue())
 		})
 
 		cm := &corev1.ConfigMap{}
@@ -109,6 +159,205 @@ var _ = Describe("Monitoring Reconcile", Ordered, func() {
 				Name:      alertManagerConfigName,
 			}, amc)
 			Expect(err).ShouldNot(HaveOccurred())
+			Expect(amc.Labels).To(Equal(am.Spec.AlertmanagerConfigSelector.MatchLabels))
+		})
+
+		It("should reconcile the ServiceMonitors successfully", func() {
+			sms := &promv1.ServiceMonitorList{}
+			err := r.Client.List(context.TODO(), sms)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(sms.Items).To(HaveLen(5))
+		})
+
+		It("should report the Dead Man's Snitch route as not generated", func() {
+			m := &addonv1alpha1.Monitoring{}
+			err := r.Client.Get(context.TODO(), types.NamespacedName{
+				Namespace: monitoring.Namespace,
+				Name:      monitoring.Name,
+			}, m)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(common.ContainCondition(m.Status.Conditions,
+				DeadMansSnitchRouteLoadedCondition, metav1.ConditionFalse)).To(BeTrue())
+			Expect(meta.FindStatusCondition(m.Status.Conditions,
+				DeadMansSnitchRouteLoadedCondition).Reason).To(Equal("ConfigNotGenerated"))
+		})
+
+		It("should report the Dead Man's Snitch route once loaded", func() {
+			generated := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "alertmanager-gpuaddon-alertmanager-generated",
+					Namespace: monitoring.Namespace,
+				},
+				Data: map[string][]byte{
+					"alertmanager.yaml.gz": gzipData(
+						"route:\n  receiver: \"null\"\n" +
+							"receivers:\n- name: \"null\"\n" +
+							"- name: test/gpuaddon-alertmanager-config/DeadMansSnitch\n"),
+				},
+			}
+			Expect(r.Client.Create(context.TODO(), generated)).To(Succeed())
+
+			for name, replicas := range map[string]int32{
+				"prometheus-gpuaddon-prometheus":     1,
+				"alertmanager-gpuaddon-alertmanager": 3,
+			} {
+				sts := &appsv1.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: monitoring.Namespace,
+					},
+					Spec: appsv1.StatefulSetSpec{
+						Replicas: pointer.Int32(replicas),
+					},
+					Status: appsv1.StatefulSetStatus{
+						ReadyReplicas: replicas,
+					},
+				}
+				Expect(r.Client.Create(context.TODO(), sts)).To(Succeed())
+			}
+
+			req := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: monitoring.Namespace,
+					Name:      monitoring.Name,
+				},
+			}
+			res, err := r.Reconcile(context.TODO(), req)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(res.RequeueAfter).To(BeZero())
+
+			m := &addonv1alpha1.Monitoring{}
+			err = r.Client.Get(context.TODO(), req.NamespacedName, m)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(common.ContainCondition(m.Status.Conditions,
+				DeadMansSnitchRouteLoadedCondition, metav1.ConditionTrue)).To(BeTrue())
+			Expect(common.ContainCondition(m.Status.Conditions,
+				PrometheusCondition, metav1.ConditionTrue)).To(BeTrue())
+			Expect(common.ContainCondition(m.Status.Conditions,
+				AlertmanagerCondition, metav1.ConditionTrue)).To(BeTrue())
+			Expect(common.ContainCondition(m.Status.Conditions,
+				AvailableCondition, metav1.ConditionTrue)).To(BeTrue())
+		})
+	})
+
+	Context("Reconcile without receiver Secrets", func() {
+		monitoring := &addonv1alpha1.Monitoring{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "test",
+			},
+		}
+		r := newTestMonitoringReconciler(monitoring)
+
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: monitoring.Namespace,
+				Name:      monitoring.Name,
+			},
+		}
+
+		It("should reconcile the rest of the monitoring stack", func() {
+			_, err := r.Reconcile(context.TODO(), req)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			m := &addonv1alpha1.Monitoring{}
+			err = r.Client.Get(context.TODO(), req.NamespacedName, m)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(common.ContainCondition(m.Status.Conditions,
+				AlertmanagerConfigCondition, metav1.ConditionTrue)).To(BeTrue())
+			Expect(common.ContainCondition(m.Status.Conditions,
+				DegradedCondition, metav1.ConditionFalse)).To(BeTrue())
+		})
+
+		It("should report the receivers that are not configured", func() {
+			m := &addonv1alpha1.Monitoring{}
+			err := r.Client.Get(context.TODO(), req.NamespacedName, m)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(common.ContainCondition(m.Status.Conditions,
+				ReceiverNotConfiguredCondition, metav1.ConditionTrue)).To(BeTrue())
+			message := meta.FindStatusCondition(m.Status.Conditions, ReceiverNotConfiguredCondition).Message
+			Expect(message).To(ContainSubstring(pagerDutyReceiverName))
+			Expect(message).To(ContainSubstring(deadMansSnitchReceiverName))
+
+			Expect(meta.FindStatusCondition(m.Status.Conditions,
+				DeadMansSnitchRouteLoadedCondition).Reason).To(Equal(receiverNotConfiguredReason))
+		})
+
+		It("should not report unconfigured receivers as unavailable", func() {
+			for name, replicas := range map[string]int32{
+				"prometheus-gpuaddon-prometheus":     1,
+				"alertmanager-gpuaddon-alertmanager": 3,
+			} {
+				sts := &appsv1.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: monitoring.Namespace,
+					},
+					Spec: appsv1.StatefulSetSpec{
+						Replicas: pointer.Int32(replicas),
+					},
+					Status: appsv1.StatefulSetStatus{
+						ReadyReplicas: replicas,
+					},
+				}
+				Expect(r.Client.Create(context.TODO(), sts)).To(Succeed())
+			}
+
+			res, err := r.Reconcile(context.TODO(), req)
+			Expect(err).ShouldNot(HaveOccurred())
+			// Only the GPU operator discovery is pending, the receiver
+			// Secrets are watched.
+			Expect(res.RequeueAfter).To(Equal(gpuOperatorDiscoveryInterval))
+
+			m := &addonv1alpha1.Monitoring{}
+			err = r.Client.Get(context.TODO(), req.NamespacedName, m)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(common.ContainCondition(m.Status.Conditions,
+				AvailableCondition, metav1.ConditionTrue)).To(BeTrue())
+		})
+
+		It("should configure the receivers once their Secrets are added", func() {
+			for name, data := range map[string]map[string][]byte{
+				common.GlobalConfig.PagerDutySecretName:      {pagerDutyKey: []byte("some-service-key")},
+				common.GlobalConfig.DeadMansSnitchSecretName: {snitchURLKey: []byte("some-snitch-url")},
+			} {
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: monitoring.Namespace,
+					},
+					Data: data,
+				}
+				Expect(r.Client.Create(context.TODO(), secret)).To(Succeed())
+				Expect(r.getMonitoringRequestsForSecret(secret)).To(ConsistOf(req))
+			}
+
+			_, err := r.Reconcile(context.TODO(), req)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			m := &addonv1alpha1.Monitoring{}
+			err = r.Client.Get(context.TODO(), req.NamespacedName, m)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(common.ContainCondition(m.Status.Conditions,
+				ReceiverNotConfiguredCondition, metav1.ConditionFalse)).To(BeTrue())
+			Expect(meta.FindStatusCondition(m.Status.Conditions,
+				DeadMansSnitchRouteLoadedCondition).Reason).To(Equal("ConfigNotGenerated"))
+		})
+
+		It("should ignore unrelated Secrets", func() {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "unrelated",
+					Namespace: monitoring.Namespace,
+				},
+			}
+			Expect(r.getMonitoringRequestsForSecret(secret)).To(BeEmpty())
 		})
 	})
 
@@ -120,6 +369,7 @@ var _ = Describe("Monitoring Reconcile", Ordered, func() {
 				Namespace:         "test",
 				DeletionTimestamp: &now,
 				UID:               types.UID("uid-uid"),
+				Finalizers:        []string{common.GlobalConfig.AddonID},
 			},
 		}
 
@@ -238,6 +488,8 @@ func newTestMonitoringReconciler(objs ...runtime.Object) *MonitoringReconciler {
 	Expect(addonv1alpha1.AddToScheme(s)).ShouldNot(HaveOccurred())
 	Expect(promv1.AddToScheme(s)).ShouldNot(HaveOccurred())
 	Expect(promv1alpha1.AddToScheme(s)).ShouldNot(HaveOccurred())
+	Expect(gpuv1.AddToScheme(s)).ShouldNot(HaveOccurred())
+	Expect(configv1.AddToScheme(s)).ShouldNot(HaveOccurred())
 
 	c := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objs...).Build()
 
@@ -246,3 +498,12 @@ func newTestMonitoringReconciler(objs ...runtime.Object) *MonitoringReconciler {
 		Scheme: s,
 	}
 }
+
+func gzipData(data string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(data))
+	Expect(err).ShouldNot(HaveOccurred())
+	Expect(w.Close()).To(Succeed())
+	return buf.Bytes()
+}
@@ -28,9 +28,9 @@ const (
 
 	prometheusKubeRBACProxyConfigMapName = "prometheus-kube-rbac-proxy-config"
 
-	kubeRBACProxyPort = 9339
+	kubeRBACProxyPort = addonv1alpha1.MonitoringPrometheusServicePort
 
-	prometheusServiceName = "gpuaddon-prometheus-service"
+	prometheusServiceName = addonv1alpha1.MonitoringPrometheusServiceName
 )
 
 func (r *MonitoringReconciler) reconcilePrometheus(
@@ -63,8 +63,23 @@ func (r *MonitoringReconciler) reconcilePrometheus(
 		prometheus = existingPrometheus
 	}
 
+	externalLabels, err := r.getClusterIdentityLabels(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	remoteWrite, err := r.getRemoteWriteSpecs(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	kubeRBACProxyImage, err := r.getKubeRBACProxyImage(ctx)
+	if err != nil {
+		return err
+	}
+
 	res, err := controllerutil.CreateOrPatch(context.TODO(), r.Client, prometheus, func() error {
-		return r.setDesiredPrometheus(r.Client, prometheus, m)
+		return r.setDesiredPrometheus(r.Client, prometheus, externalLabels, remoteWrite, kubeRBACProxyImage, m)
 	})
 	if err != nil {
 		return err
@@ -81,6 +96,9 @@ func (r *MonitoringReconciler) reconcilePrometheus(
 func (r *MonitoringReconciler) setDesiredPrometheus(
 	c client.Client,
 	prometheus *promv1.Prometheus,
+	externalLabels map[string]string,
+	remoteWrite []promv1.RemoteWriteSpec,
+	kubeRBACProxyImage string,
 	m *addonv1alpha1.Monitoring) error {
 
 	if prometheus == nil {
@@ -93,16 +111,41 @@ func (r *MonitoringReconciler) setDesiredPrometheus(
 		},
 	}
 
+	spec := m.Spec.Prometheus
+
+	replicas := addonv1alpha1.MonitoringDefaultPrometheusReplicas
+	if spec.Replicas != nil {
+		replicas = *spec.Replicas
+	}
+
+	retentionTime := addonv1alpha1.MonitoringDefaultPrometheusRetentionTime
+	if spec.RetentionTime != "" {
+		retentionTime = spec.RetentionTime
+	}
+
+	scrapeInterval := addonv1alpha1.MonitoringDefaultPrometheusScrapeInterval
+	if spec.ScrapeInterval != "" {
+		scrapeInterval = spec.ScrapeInterval
+	}
+
 	prometheus.Spec = promv1.PrometheusSpec{
 		CommonPrometheusFields: promv1.CommonPrometheusFields{
+			Replicas:               &replicas,
 			ServiceAccountName:     "prometheus-k8s",
 			ServiceMonitorSelector: &selector,
 			PodMonitorSelector:     &selector,
 			EnableAdminAPI:         false,
 			ListenLocal:            true,
+			ScrapeInterval:         promv1.Duration(scrapeInterval),
+			Storage:                getStorageSpec(spec.VolumeClaimTemplate),
 		},
-		RuleNamespaceSelector: &selector,
+		// Rules are only looked up in the addon namespace.
+		RuleSelector:  &selector,
+		Retention:     promv1.Duration(retentionTime),
+		RetentionSize: promv1.ByteSize(spec.RetentionSize),
 	}
+	prometheus.Spec.ExternalLabels = externalLabels
+	prometheus.Spec.RemoteWrite = remoteWrite
 
 	prometheus.Spec.Alerting = &promv1.AlertingSpec{
 		Alertmanagers: []promv1.AlertmanagerEndpoints{{
@@ -122,37 +165,12 @@ func (r *MonitoringReconciler) setDesiredPrometheus(
 			"memory": resource.MustParse("250Mi"),
 		},
 	}
+	if spec.Resources != nil {
+		prometheus.Spec.Resources = *spec.Resources.DeepCopy()
+	}
 
 	prometheus.Spec.Containers = []corev1.Container{
-		{
-			// TODO: adjust image tag to openshift version
-			Image: "quay.io/openshift/origin-kube-rbac-proxy:4.10.0",
-			Name:  "kube-rbac-proxy",
-			Args: []string{
-				fmt.Sprintf("--secure-listen-address=0.0.0.0:%d", kubeRBACProxyPort),
-				"--upstream=http://127.0.0.1:9090/",
-				"--logtostderr=true",
-				"--v=10",
-				"--tls-cert-file=/etc/tls-secret/tls.crt",
-				"--tls-private-key-file=/etc/tls-secret/tls.key",
-				"--client-ca-file=/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt",
-				"--config-file=/etc/kube-rbac-config/config-file.json",
-			},
-			Ports: []corev1.ContainerPort{{
-				Name:          "https",
-				ContainerPort: kubeRBACProxyPort,
-			}},
-			VolumeMounts: []corev1.VolumeMount{
-				{
-					Name:      "serving-cert",
-					MountPath: "/etc/tls-secret",
-				},
-				{
-					Name:      "kube-rbac-config",
-					MountPath: "/etc/kube-rbac-config",
-				},
-			},
-		},
+		getKubeRBACProxyContainer(kubeRBACProxyImage, spec.KubeRBACProxy),
 	}
 
 	prometheus.Spec.Volumes = []corev1.Volume{
@@ -261,25 +279,32 @@ func (r *MonitoringReconciler) setDesiredPrometheusKubeRBACProxyConfigMap(
 
 	cm.Data = map[string]string{
 		"config-file.json": (func() string {
+			type staticAuthorization struct {
+				Path            string `json:"path"`
+				ResourceRequest bool   `json:"resourceRequest"`
+				Verb            string `json:"verb"`
+			}
+
 			config := struct {
 				Authorization struct {
-					Static [2]struct {
-						Path            string `json:"path"`
-						ResourceRequest bool   `json:"resourceRequest"`
-						Verb            string `json:"verb"`
-					} `json:"static"`
+					Static []staticAuthorization `json:"static"`
 				} `json:"authorization"`
 			}{}
 
-			item := &config.Authorization.Static[0]
-			item.Verb = "get"
-			item.Path = "/metrics"
-			item.ResourceRequest = false
-
-			item = &config.Authorization.Static[1]
-			item.Verb = "get"
-			item.Path = "/federate"
-			item.ResourceRequest = false
+			// The other paths, e.g. the query endpoints used by the GPU console
+			// plugin with the token of the console user, are authorized through
+			// a SubjectAccessReview, see the nvidia-gpu-addon-prometheus-query
+			// ClusterRole.
+			for _, path := range []string{
+				"/metrics",
+				"/federate",
+			} {
+				config.Authorization.Static = append(config.Authorization.Static, staticAuthorization{
+					Path:            path,
+					ResourceRequest: false,
+					Verb:            "get",
+				})
+			}
 
 			raw, _ := json.Marshal(config)
 			return string(raw)
@@ -371,6 +396,12 @@ func (r *MonitoringReconciler) setDesiredPrometheusService(
 		},
 	}
 
+	if s.Labels == nil {
+		s.Labels = map[string]string{}
+	}
+	// Selected by the ServiceMonitor of the addon Prometheus itself.
+	s.Labels["app"] = prometheusName
+
 	s.Spec.Selector = map[string]string{
 		"app.kubernetes.io/name": "prometheus",
 	}
@@ -2,10 +2,13 @@ package monitoring
 
 import (
 	"context"
+	"encoding/json"
 
+	configv1 "github.com/openshift/api/config/v1"
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -33,6 +36,7 @@ var _ = Describe("Prometheus", Ordered, func() {
 		scheme := scheme.Scheme
 		Expect(promv1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
 		Expect(addonv1alpha1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		Expect(configv1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
 
 		var p promv1.Prometheus
 
@@ -54,6 +58,211 @@ var _ = Describe("Prometheus", Ordered, func() {
 			}, &p)
 			Expect(err).ShouldNot(HaveOccurred())
 		})
+
+		It("should use the default sizing", func() {
+			Expect(*p.Spec.Replicas).To(Equal(int32(1)))
+			Expect(p.Spec.Retention).To(Equal(promv1.Duration("24h")))
+			Expect(p.Spec.RetentionSize).To(BeEmpty())
+			Expect(p.Spec.ScrapeInterval).To(Equal(promv1.Duration("30s")))
+			Expect(p.Spec.Resources.Requests.Memory().String()).To(Equal("250Mi"))
+			Expect(p.Spec.Storage).To(BeNil())
+		})
+
+		It("should only label the alerts with the addon version outside of OpenShift", func() {
+			Expect(p.Spec.ExternalLabels).To(HaveLen(1))
+			Expect(p.Spec.ExternalLabels).To(HaveKey(addonVersionLabel))
+		})
+
+		It("should size Prometheus from the MonitoringSpec", func() {
+			storageClassName := "gp3-csi"
+			replicas := int32(2)
+			m := m.DeepCopy()
+			m.Spec.Prometheus = addonv1alpha1.MonitoringPrometheusSpec{
+				Replicas: &replicas,
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("2Gi"),
+					},
+				},
+				RetentionTime:  "15d",
+				RetentionSize:  "40GB",
+				ScrapeInterval: "1m",
+				VolumeClaimTemplate: &addonv1alpha1.MonitoringVolumeClaimTemplate{
+					StorageClassName: &storageClassName,
+					Size:             resource.MustParse("50Gi"),
+				},
+			}
+
+			err := r.reconcilePrometheus(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = r.Get(context.TODO(), types.NamespacedName{
+				Name:      "gpuaddon-prometheus",
+				Namespace: m.Namespace,
+			}, &p)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(*p.Spec.Replicas).To(Equal(int32(2)))
+			Expect(p.Spec.Retention).To(Equal(promv1.Duration("15d")))
+			Expect(p.Spec.RetentionSize).To(Equal(promv1.ByteSize("40GB")))
+			Expect(p.Spec.ScrapeInterval).To(Equal(promv1.Duration("1m")))
+			Expect(p.Spec.Resources.Requests.Memory().String()).To(Equal("2Gi"))
+			Expect(p.Spec.Resources.Limits).To(BeEmpty())
+
+			Expect(p.Spec.Storage).ToNot(BeNil())
+			pvc := p.Spec.Storage.VolumeClaimTemplate.Spec
+			Expect(*pvc.StorageClassName).To(Equal("gp3-csi"))
+			Expect(pvc.Resources.Requests.Storage().String()).To(Equal("50Gi"))
+		})
+	})
+
+	Context("Cluster identity", func() {
+		m := &addonv1alpha1.Monitoring{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "test",
+			},
+		}
+		clusterVersion := &configv1.ClusterVersion{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "version",
+			},
+			Spec: configv1.ClusterVersionSpec{
+				ClusterID: "0f7e9cc8-8d4c-4fd5-9bc3-0d1f1d0c6a11",
+			},
+		}
+		infrastructure := &configv1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster",
+			},
+			Status: configv1.InfrastructureStatus{
+				InfrastructureName: "gpu-cluster-x7k2q",
+			},
+		}
+		gpuAddon := &addonv1alpha1.GPUAddon{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      common.GlobalConfig.AddonID,
+				Namespace: m.Namespace,
+				Labels: map[string]string{
+					common.GlobalConfig.AddonID + "-version": "1.2.3",
+				},
+			},
+		}
+
+		It("should label the alerts with the cluster identity", func() {
+			r := newTestMonitoringReconciler(clusterVersion, infrastructure, gpuAddon)
+
+			err := r.reconcilePrometheus(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			p := &promv1.Prometheus{}
+			err = r.Get(context.TODO(), types.NamespacedName{
+				Name:      prometheusName,
+				Namespace: m.Namespace,
+			}, p)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(p.Spec.ExternalLabels).To(Equal(map[string]string{
+				clusterIDLabel:          "0f7e9cc8-8d4c-4fd5-9bc3-0d1f1d0c6a11",
+				infrastructureNameLabel: "gpu-cluster-x7k2q",
+				addonVersionLabel:       "1.2.3",
+			}))
+		})
+	})
+
+	Context("kube-rbac-proxy", func() {
+		m := &addonv1alpha1.Monitoring{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "test",
+			},
+		}
+		newClusterVersion := func(version string) *configv1.ClusterVersion {
+			return &configv1.ClusterVersion{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "version",
+				},
+				Status: configv1.ClusterVersionStatus{
+					History: []configv1.UpdateHistory{
+						{
+							State:   configv1.CompletedUpdate,
+							Version: version,
+						},
+					},
+				},
+			}
+		}
+
+		getSidecar := func(r *MonitoringReconciler, m *addonv1alpha1.Monitoring) corev1.Container {
+			Expect(r.reconcilePrometheus(context.TODO(), m)).To(Succeed())
+
+			p := &promv1.Prometheus{}
+			err := r.Get(context.TODO(), types.NamespacedName{
+				Name:      prometheusName,
+				Namespace: m.Namespace,
+			}, p)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(p.Spec.Containers).To(HaveLen(1))
+
+			return p.Spec.Containers[0]
+		}
+
+		It("should use the image of the OpenShift version", func() {
+			r := newTestMonitoringReconciler(newClusterVersion("4.9.7"))
+
+			container := getSidecar(r, m)
+			Expect(container.Image).To(Equal("registry.example.com/kube-rbac-proxy@sha256:4009"))
+		})
+
+		It("should use the image of the default version for an unknown OpenShift version", func() {
+			r := newTestMonitoringReconciler(newClusterVersion("4.99.0"))
+
+			container := getSidecar(r, m)
+			Expect(container.Image).To(Equal("registry.example.com/kube-rbac-proxy@sha256:4010"))
+		})
+
+		It("should use the image of the default version for an OpenShift version without image", func() {
+			r := newTestMonitoringReconciler(newClusterVersion("4.11.2"))
+
+			container := getSidecar(r, m)
+			Expect(container.Image).To(Equal("registry.example.com/kube-rbac-proxy@sha256:4010"))
+		})
+
+		It("should fail without image for the default version", func() {
+			image := common.GlobalConfig.KubeRBACProxyImage4_10
+			common.GlobalConfig.KubeRBACProxyImage4_10 = ""
+			defer func() { common.GlobalConfig.KubeRBACProxyImage4_10 = image }()
+
+			r := newTestMonitoringReconciler(newClusterVersion("4.99.0"))
+			_, err := r.getKubeRBACProxyImage(context.TODO())
+			Expect(err).To(MatchError(ContainSubstring("RELATED_IMAGE_KUBE_RBAC_PROXY_4_10")))
+		})
+
+		It("should not log every request by default", func() {
+			r := newTestMonitoringReconciler()
+
+			container := getSidecar(r, m)
+			Expect(container.Args).To(ContainElement("--v=0"))
+			Expect(container.Resources.Requests.Memory().String()).To(Equal("64Mi"))
+		})
+
+		It("should set the log level and resources from the MonitoringSpec", func() {
+			m := m.DeepCopy()
+			m.Spec.Prometheus.KubeRBACProxy = addonv1alpha1.MonitoringKubeRBACProxySpec{
+				LogLevel: 10,
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			}
+			r := newTestMonitoringReconciler()
+
+			container := getSidecar(r, m)
+			Expect(container.Args).To(ContainElement("--v=10"))
+			Expect(container.Resources.Requests.Memory().String()).To(Equal("32Mi"))
+			Expect(container.Resources.Limits).To(BeEmpty())
+		})
 	})
 
 	Context("Delete", func() {
@@ -131,6 +340,26 @@ var _ = Describe("Prometheus KubeRBACProxy ConfigMap", Ordered, func() {
 				Namespace: m.Namespace,
 			}, &cm)
 			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cm.Data).To(HaveKey("config-file.json"))
+		})
+
+		It("should leave the Prometheus query endpoints to RBAC", func() {
+			config := struct {
+				Authorization struct {
+					Static []struct {
+						Path string `json:"path"`
+						Verb string `json:"verb"`
+					} `json:"static"`
+				} `json:"authorization"`
+			}{}
+			Expect(json.Unmarshal([]byte(cm.Data["config-file.json"]), &config)).To(Succeed())
+
+			paths := []string{}
+			for _, item := range config.Authorization.Static {
+				Expect(item.Verb).To(Equal("get"))
+				paths = append(paths, item.Path)
+			}
+			Expect(paths).To(ConsistOf("/metrics", "/federate"))
 		})
 	})
 
@@ -0,0 +1,173 @@
+package monitoring
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strconv"
+
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+)
+
+const (
+	prometheusRuleName = "gpuaddon-prometheus-rules"
+
+	alertCatalogVersionAnnotation = "nvidia.addons.rh-ecosystem-edge.io/alert-catalog-version"
+)
+
+//go:embed rules/alerts.yaml
+var alertCatalogYAML []byte
+
+// alertCatalog is the versioned set of alerting rules shipped with the addon.
+type alertCatalog struct {
+	Version int                `json:"version"`
+	Groups  []promv1.RuleGroup `json:"groups"`
+}
+
+func loadAlertCatalog() (*alertCatalog, error) {
+	catalog := &alertCatalog{}
+	if err := yaml.UnmarshalStrict(alertCatalogYAML, catalog); err != nil {
+		return nil, fmt.Errorf("failed to parse the alert catalog: %w", err)
+	}
+
+	if catalog.Version < 1 {
+		return nil, fmt.Errorf("invalid alert catalog version %d", catalog.Version)
+	}
+
+	return catalog, nil
+}
+
+func (r *MonitoringReconciler) reconcilePrometheusRule(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring) error {
+
+	logger := log.FromContext(ctx, "Reconcile Step", "PrometheusRule CR")
+	logger.Info("Reconciling PrometheusRule")
+
+	catalog, err := loadAlertCatalog()
+	if err != nil {
+		return err
+	}
+
+	// The alerts of the addon Prometheus are labeled through its external
+	// labels, which are not set by the addon in UserWorkload mode.
+	var identityLabels map[string]string
+	if isUserWorkloadMode(m) {
+		identityLabels, err = r.getClusterIdentityLabels(ctx, m)
+		if err != nil {
+			return err
+		}
+	}
+
+	rule := &promv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      prometheusRuleName,
+			Namespace: m.Namespace,
+		},
+	}
+
+	res, err := controllerutil.CreateOrPatch(ctx, r.Client, rule, func() error {
+		return r.setDesiredPrometheusRule(r.Client, rule, catalog, identityLabels, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("PrometheusRule reconciled successfully",
+		"name", rule.Name,
+		"namespace", rule.Namespace,
+		"catalogVersion", catalog.Version,
+		"result", res)
+
+	return nil
+}
+
+func (r *MonitoringReconciler) setDesiredPrometheusRule(
+	c client.Client,
+	rule *promv1.PrometheusRule,
+	catalog *alertCatalog,
+	identityLabels map[string]string,
+	m *addonv1alpha1.Monitoring) error {
+
+	if rule == nil {
+		return errors.New("prometheusrule cannot be nil")
+	}
+
+	if rule.Labels == nil {
+		rule.Labels = map[string]string{}
+	}
+	// Selected by the addon Prometheus rule selector.
+	rule.Labels["app"] = prometheusName
+	if isUserWorkloadMode(m) {
+		rule.Labels[ruleEvaluationScopeLabel] = ruleEvaluationScopeLeaf
+	} else {
+		delete(rule.Labels, ruleEvaluationScopeLabel)
+	}
+
+	if rule.Annotations == nil {
+		rule.Annotations = map[string]string{}
+	}
+	rule.Annotations[alertCatalogVersionAnnotation] = strconv.Itoa(catalog.Version)
+
+	groups := make([]promv1.RuleGroup, 0, len(catalog.Groups)+2)
+	for _, group := range catalog.Groups {
+		groups = append(groups, *group.DeepCopy())
+	}
+	groups = append(groups,
+		getShowbackRuleGroup(m.Spec.Showback),
+		getGPUHealthRuleGroup(m.Spec.GPUHealth))
+
+	// The prometheus-operator restricts the AlertmanagerConfig routes to
+	// alerts labeled with its namespace, which alerts on aggregated or
+	// constant expressions would otherwise lack.
+	for i := range groups {
+		for j := range groups[i].Rules {
+			rule := &groups[i].Rules[j]
+			if rule.Alert == "" {
+				continue
+			}
+			if rule.Labels == nil {
+				rule.Labels = map[string]string{}
+			}
+			rule.Labels["namespace"] = m.Namespace
+			for name, value := range identityLabels {
+				rule.Labels[name] = value
+			}
+		}
+	}
+
+	rule.Spec = promv1.PrometheusRuleSpec{
+		Groups: groups,
+	}
+
+	return ctrl.SetControllerReference(m, rule, c.Scheme())
+}
+
+func (r *MonitoringReconciler) deletePrometheusRule(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring) error {
+
+	rule := &promv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      prometheusRuleName,
+			Namespace: m.Namespace,
+		},
+	}
+
+	err := r.Delete(ctx, rule)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete PrometheusRule %s in %s: %w", rule.Name, rule.Namespace, err)
+	}
+
+	return nil
+}
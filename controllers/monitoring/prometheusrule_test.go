@@ -0,0 +1,242 @@
+package monitoring
+
+import (
+	"context"
+
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+var _ = Describe("Alert catalog", func() {
+	catalog, err := loadAlertCatalog()
+
+	It("should load", func() {
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(catalog.Version).To(BeNumerically(">=", 1))
+		Expect(catalog.Groups).ToNot(BeEmpty())
+	})
+
+	It("should have valid rules", func() {
+		names := map[string]bool{}
+
+		for _, group := range catalog.Groups {
+			Expect(group.Name).ToNot(BeEmpty())
+
+			for _, rule := range group.Rules {
+				Expect(rule.Alert).ToNot(BeEmpty())
+				Expect(names).ToNot(HaveKey(rule.Alert), "duplicate alert %s", rule.Alert)
+				names[rule.Alert] = true
+
+				_, err := parser.ParseExpr(rule.Expr.String())
+				Expect(err).ShouldNot(HaveOccurred(), "alert %s", rule.Alert)
+
+				if rule.For != "" {
+					_, err := model.ParseDuration(rule.For)
+					Expect(err).ShouldNot(HaveOccurred(), "alert %s", rule.Alert)
+				}
+
+				Expect(rule.Labels).To(HaveKey("severity"), "alert %s", rule.Alert)
+				Expect(rule.Annotations).To(HaveKey("summary"), "alert %s", rule.Alert)
+			}
+		}
+	})
+
+	It("should define the always firing Dead Man's Snitch watchdog", func() {
+		var watchdog *promv1.Rule
+		for _, group := range catalog.Groups {
+			for i := range group.Rules {
+				if group.Rules[i].Alert == deadMansSnitchReceiverName {
+					watchdog = &group.Rules[i]
+				}
+			}
+		}
+
+		Expect(watchdog).ToNot(BeNil())
+		Expect(watchdog.Expr.StrVal).To(Equal("vector(1)"))
+		Expect(watchdog.For).To(BeEmpty())
+	})
+
+	It("should define the alerts routed to PagerDuty", func() {
+		names := []string{}
+		for _, group := range append(catalog.Groups, getGPUHealthRuleGroup(addonv1alpha1.MonitoringGPUHealthSpec{})) {
+			for _, rule := range group.Rules {
+				names = append(names, rule.Alert)
+			}
+		}
+
+		Expect(names).To(ContainElements(pagerdutyAlerts))
+	})
+})
+
+var _ = Describe("GPU health rules", func() {
+	It("should have valid rules", func() {
+		group := getGPUHealthRuleGroup(addonv1alpha1.MonitoringGPUHealthSpec{})
+		Expect(group.Rules).To(HaveLen(6))
+
+		for _, rule := range group.Rules {
+			_, err := parser.ParseExpr(rule.Expr.String())
+			Expect(err).ShouldNot(HaveOccurred(), "alert %s", rule.Alert)
+
+			if rule.For != "" {
+				_, err := model.ParseDuration(rule.For)
+				Expect(err).ShouldNot(HaveOccurred(), "alert %s", rule.Alert)
+			}
+
+			Expect(rule.Labels["severity"]).To(BeElementOf("warning", "critical"), "alert %s", rule.Alert)
+			if rule.Labels["severity"] == "critical" {
+				Expect(pagerdutyAlerts).To(ContainElement(rule.Alert))
+			} else {
+				Expect(pagerdutyAlerts).ToNot(ContainElement(rule.Alert))
+			}
+		}
+	})
+
+	It("should use the thresholds of the MonitoringSpec", func() {
+		eccDoubleBitErrors := int32(3)
+		group := getGPUHealthRuleGroup(addonv1alpha1.MonitoringGPUHealthSpec{
+			ThermalThrottlingPercent: 25,
+			PowerViolationPercent:    50,
+			ThrottlingFor:            "1h",
+			EccDoubleBitErrors:       &eccDoubleBitErrors,
+		})
+
+		rules := map[string]promv1.Rule{}
+		for _, rule := range group.Rules {
+			rules[rule.Alert] = rule
+		}
+
+		Expect(rules["NVIDIAGPUAddonGPUThermalThrottling"].Expr.StrVal).To(HaveSuffix("> 25"))
+		Expect(rules["NVIDIAGPUAddonGPUThermalThrottling"].For).To(Equal("1h"))
+		Expect(rules["NVIDIAGPUAddonGPUPowerViolation"].Expr.StrVal).To(HaveSuffix("> 50"))
+		Expect(rules["NVIDIAGPUAddonGPUPowerViolation"].For).To(Equal("1h"))
+		Expect(rules["NVIDIAGPUAddonGPUEccDoubleBitErrors"].Expr.StrVal).To(HaveSuffix("> 3"))
+	})
+
+	It("should only report the XID errors when they change", func() {
+		group := getGPUHealthRuleGroup(addonv1alpha1.MonitoringGPUHealthSpec{})
+
+		for _, rule := range group.Rules {
+			if rule.Alert != "NVIDIAGPUAddonGPUXIDError" && rule.Alert != "NVIDIAGPUAddonGPUFallenOffBus" {
+				continue
+			}
+
+			expr, err := parser.ParseExpr(rule.Expr.String())
+			Expect(err).ShouldNot(HaveOccurred())
+
+			changes := false
+			parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+				call, ok := node.(*parser.Call)
+				if !ok || call.Func.Name != "changes" {
+					return nil
+				}
+				selector := call.Args[0].(*parser.MatrixSelector).VectorSelector.(*parser.VectorSelector)
+				changes = selector.Name == "DCGM_FI_DEV_XID_ERRORS"
+				return nil
+			})
+			Expect(changes).To(BeTrue(), "alert %s", rule.Alert)
+		}
+	})
+})
+
+var _ = Describe("PrometheusRule", Ordered, func() {
+	Context("Reconcile", func() {
+		common.ProcessConfig()
+
+		m := &addonv1alpha1.Monitoring{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "test",
+			},
+		}
+		r := &MonitoringReconciler{}
+
+		scheme := scheme.Scheme
+		Expect(promv1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		Expect(addonv1alpha1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+
+		It("should create the PrometheusRule CR", func() {
+			c := fake.
+				NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects().
+				Build()
+
+			r.Client = c
+
+			err := r.reconcilePrometheusRule(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			rule := &promv1.PrometheusRule{}
+			err = c.Get(context.TODO(), types.NamespacedName{
+				Name:      "gpuaddon-prometheus-rules",
+				Namespace: m.Namespace,
+			}, rule)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(rule.Labels).To(HaveKeyWithValue("app", prometheusName))
+			Expect(rule.Annotations).To(HaveKeyWithValue(alertCatalogVersionAnnotation, "5"))
+			Expect(rule.Spec.Groups).ToNot(BeEmpty())
+			Expect(rule.Spec.Groups[len(rule.Spec.Groups)-1].Name).To(Equal(gpuHealthRuleGroupName))
+
+			for _, group := range rule.Spec.Groups {
+				for _, alert := range group.Rules {
+					if alert.Alert == "" {
+						continue
+					}
+					Expect(alert.Labels).To(HaveKeyWithValue("namespace", m.Namespace), "alert %s", alert.Alert)
+				}
+			}
+		})
+	})
+
+	Context("Delete", func() {
+		m := &addonv1alpha1.Monitoring{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "test",
+			},
+		}
+		r := &MonitoringReconciler{}
+
+		scheme := scheme.Scheme
+
+		rule := &promv1.PrometheusRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      prometheusRuleName,
+				Namespace: m.Namespace,
+			},
+		}
+
+		It("should delete the PrometheusRule CR", func() {
+			c := fake.
+				NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(rule).
+				Build()
+
+			r.Client = c
+
+			err := r.deletePrometheusRule(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = c.Get(context.TODO(), types.NamespacedName{
+				Name:      prometheusRuleName,
+				Namespace: m.Namespace,
+			}, rule)
+			Expect(err).Should(HaveOccurred())
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+})
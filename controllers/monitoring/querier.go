@@ -0,0 +1,61 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/prometheus"
+)
+
+var (
+	// thanosQuerierTenancyAddress is the Thanos Querier port of the OpenShift
+	// monitoring restricting the queries to the namespace of the caller.
+	thanosQuerierTenancyAddress = fmt.Sprintf("https://%s.%s.svc:%d",
+		addonv1alpha1.MonitoringThanosQuerierServiceName,
+		addonv1alpha1.MonitoringThanosQuerierServiceNamespace,
+		addonv1alpha1.MonitoringThanosQuerierTenancyPort)
+)
+
+// PrometheusQuerier runs instant queries against the Prometheus collecting
+// the addon metrics of a Monitoring CR.
+type PrometheusQuerier interface {
+	Query(ctx context.Context, m *addonv1alpha1.Monitoring, query string, ts time.Time) (model.Vector, error)
+}
+
+// InClusterPrometheusQuerier queries the addon Prometheus through its
+// kube-rbac-proxy, or the Thanos Querier of the OpenShift monitoring in
+// UserWorkload mode.
+type InClusterPrometheusQuerier struct{}
+
+// The tenancy port of the Thanos Querier authorizes the queries of a
+// namespace to those allowed to get its pod metrics.
+//+kubebuilder:rbac:groups=metrics.k8s.io,namespace=system,resources=pods,verbs=get
+
+// The kube-rbac-proxy of the addon Prometheus authorizes the queries through
+// a SubjectAccessReview of their path.
+//+kubebuilder:rbac:urls=/api/v1/query;/api/v1/query_range,verbs=get
+
+func (q *InClusterPrometheusQuerier) Query(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring,
+	query string,
+	ts time.Time) (model.Vector, error) {
+
+	address := fmt.Sprintf("https://%s.%s.svc:%d", prometheusServiceName, m.Namespace, kubeRBACProxyPort)
+	namespace := ""
+	if isUserWorkloadMode(m) {
+		address = thanosQuerierTenancyAddress
+		namespace = m.Namespace
+	}
+
+	c, err := prometheus.NewInClusterClient(address, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Query(ctx, query, ts)
+}
@@ -0,0 +1,225 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	promv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+)
+
+// getUserReceivers translates the receivers of the MonitoringSpec. The
+// prometheus-operator rejects an AlertmanagerConfig referencing a missing
+// Secret key, so the receivers whose Secrets are missing or incomplete are
+// omitted and recorded in notConfigured instead.
+func (r *MonitoringReconciler) getUserReceivers(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring,
+	notConfigured map[string]string) ([]promv1alpha1.Receiver, error) {
+
+	receivers := []promv1alpha1.Receiver{}
+
+	for _, spec := range m.Spec.Receivers {
+		if isBuiltInReceiver(spec.Name) {
+			notConfigured[spec.Name] = "the receiver name is reserved for the built-in receivers"
+			continue
+		}
+
+		if _, exists := notConfigured[spec.Name]; exists {
+			continue
+		}
+
+		if receiverConfigured(receivers, spec.Name) {
+			notConfigured[spec.Name] = "the receiver is defined more than once"
+			continue
+		}
+
+		if spec.Slack == nil && spec.Email == nil && spec.Webhook == nil && spec.OpsGenie == nil {
+			notConfigured[spec.Name] = "no Slack, email, webhook or OpsGenie configuration"
+			continue
+		}
+
+		var reason string
+		for _, selector := range getReceiverSecretKeySelectors(spec) {
+			var notConfiguredErr *secretNotConfiguredError
+
+			err := r.checkSecretKey(ctx, selector, m.Namespace)
+			if errors.As(err, &notConfiguredErr) {
+				reason = err.Error()
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		if reason != "" {
+			notConfigured[spec.Name] = reason
+			continue
+		}
+
+		receivers = append(receivers, getUserReceiver(spec))
+	}
+
+	return receivers, nil
+}
+
+func getUserReceiver(spec addonv1alpha1.MonitoringReceiver) promv1alpha1.Receiver {
+	receiver := promv1alpha1.Receiver{
+		Name: spec.Name,
+	}
+
+	if spec.Slack != nil {
+		receiver.SlackConfigs = []promv1alpha1.SlackConfig{{
+			APIURL:  spec.Slack.APIURL.DeepCopy(),
+			Channel: spec.Slack.Channel,
+		}}
+	}
+
+	if spec.Email != nil {
+		receiver.EmailConfigs = []promv1alpha1.EmailConfig{{
+			To:           spec.Email.To,
+			From:         spec.Email.From,
+			Smarthost:    spec.Email.Smarthost,
+			AuthUsername: spec.Email.AuthUsername,
+			AuthPassword: spec.Email.AuthPassword.DeepCopy(),
+		}}
+	}
+
+	if spec.Webhook != nil {
+		receiver.WebhookConfigs = []promv1alpha1.WebhookConfig{{
+			URLSecret: spec.Webhook.URL.DeepCopy(),
+		}}
+	}
+
+	if spec.OpsGenie != nil {
+		receiver.OpsGenieConfigs = []promv1alpha1.OpsGenieConfig{{
+			APIKey: spec.OpsGenie.APIKey.DeepCopy(),
+			APIURL: spec.OpsGenie.APIURL,
+		}}
+	}
+
+	return receiver
+}
+
+// getUserRoutes translates the routes of the MonitoringSpec. Routes to
+// receivers which are not configured are dropped, the receivers being
+// recorded in notConfigured when they are not defined at all.
+func getUserRoutes(
+	m *addonv1alpha1.Monitoring,
+	receivers []promv1alpha1.Receiver,
+	notConfigured map[string]string) []promv1alpha1.Route {
+
+	routes := []promv1alpha1.Route{}
+
+	for _, spec := range m.Spec.Routes {
+		if !receiverConfigured(receivers, spec.Receiver) {
+			if _, exists := notConfigured[spec.Receiver]; !exists {
+				notConfigured[spec.Receiver] = "the receiver of a route is not defined in the receivers"
+			}
+			continue
+		}
+
+		route := promv1alpha1.Route{
+			Receiver: spec.Receiver,
+			Continue: spec.Continue,
+		}
+		if len(spec.Severities) > 0 {
+			route.Matchers = append(route.Matchers, promv1alpha1.Matcher{
+				Name:      "severity",
+				Value:     getRegexMatcher(spec.Severities),
+				MatchType: promv1alpha1.MatchRegexp,
+			})
+		}
+		if len(spec.AlertNames) > 0 {
+			route.Matchers = append(route.Matchers, promv1alpha1.Matcher{
+				Name:      "alertname",
+				Value:     getRegexMatcher(spec.AlertNames),
+				MatchType: promv1alpha1.MatchRegexp,
+			})
+		}
+
+		routes = append(routes, route)
+	}
+
+	return routes
+}
+
+func (r *MonitoringReconciler) checkSecretKey(
+	ctx context.Context,
+	selector corev1.SecretKeySelector,
+	namespace string) error {
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      selector.Name,
+		Namespace: namespace,
+	}, secret); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return &secretNotConfiguredError{
+				reason: fmt.Sprintf("secret %s not found in %s", selector.Name, namespace),
+			}
+		}
+		return fmt.Errorf("unable to get secret %s in %s: %w", selector.Name, namespace, err)
+	}
+
+	if len(secret.Data[selector.Key]) == 0 {
+		return &secretNotConfiguredError{
+			reason: fmt.Sprintf("entry %s is missing from secret %s in %s", selector.Key, selector.Name, namespace),
+		}
+	}
+
+	return nil
+}
+
+func getReceiverSecretKeySelectors(spec addonv1alpha1.MonitoringReceiver) []corev1.SecretKeySelector {
+	selectors := []corev1.SecretKeySelector{}
+
+	if spec.Slack != nil {
+		selectors = append(selectors, spec.Slack.APIURL)
+	}
+	if spec.Email != nil && spec.Email.AuthPassword != nil {
+		selectors = append(selectors, *spec.Email.AuthPassword)
+	}
+	if spec.Webhook != nil {
+		selectors = append(selectors, spec.Webhook.URL)
+	}
+	if spec.OpsGenie != nil {
+		selectors = append(selectors, spec.OpsGenie.APIKey)
+	}
+
+	return selectors
+}
+
+// getReceiverSecretNames returns the names of the Secrets referenced by the
+// receivers of the MonitoringSpec.
+func getReceiverSecretNames(m *addonv1alpha1.Monitoring) []string {
+	names := []string{}
+	for _, spec := range m.Spec.Receivers {
+		for _, selector := range getReceiverSecretKeySelectors(spec) {
+			names = append(names, selector.Name)
+		}
+	}
+	return names
+}
+
+func isBuiltInReceiver(name string) bool {
+	switch name {
+	case nullReceiverName, pagerDutyReceiverName, deadMansSnitchReceiverName:
+		return true
+	}
+	return false
+}
+
+func receiverConfigured(receivers []promv1alpha1.Receiver, name string) bool {
+	for _, receiver := range receivers {
+		if receiver.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+
+	promv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+var _ = Describe("User alert receivers", func() {
+	common.ProcessConfig()
+
+	secretKey := func(name, key string) corev1.SecretKeySelector {
+		return corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  key,
+		}
+	}
+
+	slackSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "slack",
+			Namespace: "test",
+		},
+		Data: map[string][]byte{
+			"url": []byte("https://hooks.slack.example.com/test"),
+		},
+	}
+	pagerDutySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.GlobalConfig.PagerDutySecretName,
+			Namespace: "test",
+		},
+		Data: map[string][]byte{
+			pagerDutyKey: []byte("some-service-key"),
+		},
+	}
+
+	newMonitoring := func() *addonv1alpha1.Monitoring {
+		return &addonv1alpha1.Monitoring{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "test",
+			},
+			Spec: addonv1alpha1.MonitoringSpec{
+				Receivers: []addonv1alpha1.MonitoringReceiver{
+					{
+						Name: "team-slack",
+						Slack: &addonv1alpha1.MonitoringSlackConfig{
+							APIURL:  secretKey("slack", "url"),
+							Channel: "#gpu-alerts",
+						},
+					},
+					{
+						Name: "team-email",
+						Email: &addonv1alpha1.MonitoringEmailConfig{
+							To:        "gpu-team@example.com",
+							From:      "alertmanager@example.com",
+							Smarthost: "smtp.example.com:587",
+						},
+					},
+					{
+						Name: "team-webhook",
+						Webhook: &addonv1alpha1.MonitoringWebhookConfig{
+							URL: secretKey("webhook", "url"),
+						},
+					},
+					{
+						Name: "team-opsgenie",
+						OpsGenie: &addonv1alpha1.MonitoringOpsGenieConfig{
+							APIKey: secretKey("slack", "opsgenie-key"),
+						},
+					},
+				},
+				Routes: []addonv1alpha1.MonitoringRoute{
+					{
+						Receiver:   "team-slack",
+						Severities: []string{"critical", "warning"},
+						Continue:   true,
+					},
+					{
+						Receiver:   "team-email",
+						AlertNames: []string{"NVIDIAGPUAddonGPUFallenOffBus"},
+					},
+					{
+						Receiver: "team-webhook",
+					},
+					{
+						Receiver: "undefined",
+					},
+				},
+			},
+		}
+	}
+
+	getRoutes := func(amc *promv1alpha1.AlertmanagerConfig) []promv1alpha1.Route {
+		routes := []promv1alpha1.Route{}
+		for _, raw := range amc.Spec.Route.Routes {
+			route := promv1alpha1.Route{}
+			Expect(json.Unmarshal(raw.Raw, &route)).To(Succeed())
+			routes = append(routes, route)
+		}
+		return routes
+	}
+
+	It("should merge the configured receivers and routes with the built-in ones", func() {
+		m := newMonitoring()
+		r := newTestMonitoringReconciler(m, slackSecret, pagerDutySecret)
+
+		receivers, err := r.reconcileAlertManagerConfig(context.TODO(), m)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		amc := &promv1alpha1.AlertmanagerConfig{}
+		err = r.Get(context.TODO(), types.NamespacedName{
+			Name:      alertManagerConfigName,
+			Namespace: m.Namespace,
+		}, amc)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		names := []string{}
+		for _, receiver := range amc.Spec.Receivers {
+			names = append(names, receiver.Name)
+		}
+		Expect(names).To(Equal([]string{"null", pagerDutyReceiverName, "team-slack", "team-email"}))
+		Expect(amc.Spec.Receivers[2].SlackConfigs[0].Channel).To(Equal("#gpu-alerts"))
+		Expect(amc.Spec.Receivers[3].EmailConfigs[0].Smarthost).To(Equal("smtp.example.com:587"))
+
+		routes := getRoutes(amc)
+		Expect(routes).To(HaveLen(4))
+		Expect(routes[0].Receiver).To(Equal(nullReceiverName))
+		Expect(routes[1].Receiver).To(Equal(pagerDutyReceiverName))
+		Expect(routes[1].Continue).To(BeTrue())
+		Expect(routes[2].Receiver).To(Equal("team-slack"))
+		Expect(routes[2].Continue).To(BeTrue())
+		Expect(routes[2].Matchers).To(ConsistOf(promv1alpha1.Matcher{
+			Name:      "severity",
+			Value:     "^critical$|^warning$",
+			MatchType: promv1alpha1.MatchRegexp,
+		}))
+		Expect(routes[3].Receiver).To(Equal("team-email"))
+		Expect(routes[3].Matchers[0].Name).To(Equal("alertname"))
+
+		Expect(receivers.notConfigured).To(HaveKeyWithValue("team-webhook", ContainSubstring("secret webhook not found")))
+		Expect(receivers.notConfigured).To(HaveKeyWithValue("team-opsgenie", ContainSubstring("entry opsgenie-key is missing")))
+		Expect(receivers.notConfigured).To(HaveKey("undefined"))
+		Expect(receivers.notConfigured).To(HaveKey(deadMansSnitchReceiverName))
+	})
+
+	It("should not let a receiver replace a built-in receiver", func() {
+		m := newMonitoring()
+		m.Spec.Receivers[0].Name = pagerDutyReceiverName
+		m.Spec.Routes = nil
+		r := newTestMonitoringReconciler(m, slackSecret)
+
+		receivers, err := r.getAlertReceivers(context.TODO(), m)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(receivers.notConfigured).To(HaveKeyWithValue(pagerDutyReceiverName, ContainSubstring("reserved")))
+		for _, receiver := range receivers.user {
+			Expect(receiver.Name).ToNot(Equal(pagerDutyReceiverName))
+		}
+	})
+
+	It("should reconcile the Monitoring CRs referencing a receiver Secret", func() {
+		m := newMonitoring()
+		r := newTestMonitoringReconciler(m)
+
+		webhookSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "webhook",
+				Namespace: m.Namespace,
+			},
+		}
+		Expect(r.getMonitoringRequestsForSecret(webhookSecret)).To(HaveLen(1))
+
+		webhookSecret.Namespace = "other"
+		Expect(r.getMonitoringRequestsForSecret(webhookSecret)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,256 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+const (
+	// remoteWriteName is the remote_name label of the remote storage metrics
+	// of the addon Prometheus.
+	remoteWriteName = "gpuaddon"
+
+	// remoteWriteCheckInterval is how often the remote write status is
+	// refreshed while the remote write is configured.
+	remoteWriteCheckInterval = 5 * time.Minute
+
+	remoteWriteRateWindow = "5m"
+
+	remoteWriteSamplesMetric       = "prometheus_remote_storage_samples_total"
+	remoteWriteSamplesFailedMetric = "prometheus_remote_storage_samples_failed_total"
+)
+
+var (
+	remoteWriteDefaultAllowlist = []string{
+		"DCGM_FI_.*",
+		"gpu_operator_.*",
+	}
+)
+
+// getRemoteWriteSpecs returns the remote write configuration of the addon
+// Prometheus. The prometheus-operator does not reconcile a Prometheus whose
+// remote write references a missing Secret key, so the remote write is
+// omitted until its Secrets are complete.
+func (r *MonitoringReconciler) getRemoteWriteSpecs(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring) ([]promv1.RemoteWriteSpec, error) {
+
+	logger := log.FromContext(ctx, "Reconcile Step", "Prometheus CR")
+
+	spec := m.Spec.RemoteWrite
+	if spec == nil {
+		return nil, nil
+	}
+
+	var notConfiguredErr *secretNotConfiguredError
+	if err := r.checkRemoteWriteSecrets(ctx, m); err != nil {
+		if errors.As(err, &notConfiguredErr) {
+			logger.Info("Remote write not configured, omitting it", "reason", err.Error())
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	allowlist := remoteWriteDefaultAllowlist
+	if len(spec.MetricsAllowlist) > 0 {
+		allowlist = spec.MetricsAllowlist
+	}
+
+	remoteWrite := promv1.RemoteWriteSpec{
+		Name: remoteWriteName,
+		URL:  spec.URL,
+		WriteRelabelConfigs: []promv1.RelabelConfig{
+			{
+				Action:       "keep",
+				SourceLabels: []promv1.LabelName{"__name__"},
+				Regex:        fmt.Sprintf("(%s)", strings.Join(allowlist, "|")),
+			},
+		},
+	}
+
+	if spec.BasicAuth != nil {
+		remoteWrite.BasicAuth = &promv1.BasicAuth{
+			Username: spec.BasicAuth.Username,
+			Password: spec.BasicAuth.Password,
+		}
+	}
+
+	if spec.BearerToken != nil {
+		remoteWrite.Authorization = &promv1.Authorization{
+			SafeAuthorization: promv1.SafeAuthorization{
+				Type:        "Bearer",
+				Credentials: spec.BearerToken.DeepCopy(),
+			},
+		}
+	}
+
+	if spec.TLS != nil {
+		remoteWrite.TLSConfig = &promv1.TLSConfig{
+			SafeTLSConfig: promv1.SafeTLSConfig{
+				KeySecret:          spec.TLS.Key.DeepCopy(),
+				ServerName:         spec.TLS.ServerName,
+				InsecureSkipVerify: spec.TLS.InsecureSkipVerify,
+			},
+		}
+		if spec.TLS.CA != nil {
+			remoteWrite.TLSConfig.CA.Secret = spec.TLS.CA.DeepCopy()
+		}
+		if spec.TLS.Cert != nil {
+			remoteWrite.TLSConfig.Cert.Secret = spec.TLS.Cert.DeepCopy()
+		}
+	}
+
+	return []promv1.RemoteWriteSpec{remoteWrite}, nil
+}
+
+// getRemoteWriteCondition reports whether the addon Prometheus sends the
+// samples to the remote write endpoint, as told by its own remote storage
+// metrics, which it scrapes. There is no addon Prometheus in UserWorkload
+// mode.
+func (r *MonitoringReconciler) getRemoteWriteCondition(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring) (metav1.Condition, error) {
+
+	logger := log.FromContext(ctx, "Reconcile Step", "Remote write")
+
+	if m.Spec.RemoteWrite == nil {
+		RemoteWriteUp.Set(0)
+		return common.NewCondition(
+			RemoteWriteCondition,
+			metav1.ConditionFalse,
+			"NotConfigured",
+			"The remote write is not configured"), nil
+	}
+
+	if isUserWorkloadMode(m) {
+		RemoteWriteUp.Set(0)
+		return common.NewCondition(
+			RemoteWriteCondition,
+			metav1.ConditionFalse,
+			"NotSupportedInMode",
+			"The remote write is part of the user-workload monitoring configuration in UserWorkload mode"), nil
+	}
+
+	var notConfiguredErr *secretNotConfiguredError
+	if err := r.checkRemoteWriteSecrets(ctx, m); err != nil {
+		if !errors.As(err, &notConfiguredErr) {
+			return metav1.Condition{}, err
+		}
+
+		RemoteWriteUp.Set(0)
+		RemoteWriteFailures.WithLabelValues("SecretMissing").Inc()
+
+		return common.NewCondition(
+			RemoteWriteCondition,
+			metav1.ConditionFalse,
+			"SecretMissing",
+			err.Error()), nil
+	}
+
+	if r.Prometheus == nil {
+		RemoteWriteUp.Set(0)
+		return common.NewCondition(
+			RemoteWriteCondition,
+			metav1.ConditionFalse,
+			"QuerierNotConfigured",
+			"No Prometheus querier is configured to check the remote write"), nil
+	}
+
+	now := time.Now()
+	sent, err := r.Prometheus.Query(ctx, m, getRemoteWriteRateQuery(remoteWriteSamplesMetric), now)
+	if err == nil && len(sent) > 0 {
+		var failed model.Vector
+		failed, err = r.Prometheus.Query(ctx, m, getRemoteWriteRateQuery(remoteWriteSamplesFailedMetric), now)
+		if err == nil && len(failed) > 0 && failed[0].Value > 0 {
+			RemoteWriteUp.Set(0)
+			RemoteWriteFailures.WithLabelValues("SamplesFailing").Inc()
+
+			return common.NewCondition(
+				RemoteWriteCondition,
+				metav1.ConditionFalse,
+				"SamplesFailing",
+				fmt.Sprintf("The addon Prometheus failed to send %s samples/s to the remote write endpoint %s over the last %s",
+					failed[0].Value, m.Spec.RemoteWrite.URL, remoteWriteRateWindow)), nil
+		}
+	}
+	if err != nil {
+		RemoteWriteUp.Set(0)
+		logger.Info("Failed to query the remote write metrics, retrying later", "error", err.Error())
+
+		return common.NewCondition(
+			RemoteWriteCondition,
+			metav1.ConditionFalse,
+			"QueryFailed",
+			err.Error()), nil
+	}
+
+	// The remote storage metrics only show up once the addon Prometheus
+	// loaded the remote write configuration and got scraped.
+	if len(sent) == 0 {
+		RemoteWriteUp.Set(0)
+		return common.NewCondition(
+			RemoteWriteCondition,
+			metav1.ConditionFalse,
+			"NoMetrics",
+			"The addon Prometheus does not report its remote write metrics yet"), nil
+	}
+
+	RemoteWriteUp.Set(1)
+
+	return common.NewCondition(
+		RemoteWriteCondition,
+		metav1.ConditionTrue,
+		"SamplesSent",
+		fmt.Sprintf("The addon Prometheus sends the samples to the remote write endpoint %s without failures",
+			m.Spec.RemoteWrite.URL)), nil
+}
+
+// getRemoteWriteRateQuery returns the per-second rate of a remote storage
+// counter of the addon Prometheus remote write.
+func getRemoteWriteRateQuery(metric string) string {
+	return fmt.Sprintf(`sum(rate(%s{remote_name="%s"}[%s]))`, metric, remoteWriteName, remoteWriteRateWindow)
+}
+
+func (r *MonitoringReconciler) checkRemoteWriteSecrets(ctx context.Context, m *addonv1alpha1.Monitoring) error {
+	for _, selector := range getRemoteWriteSecretKeySelectors(m.Spec.RemoteWrite) {
+		if err := r.checkSecretKey(ctx, selector, m.Namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getRemoteWriteSecretKeySelectors(spec *addonv1alpha1.MonitoringRemoteWriteSpec) []corev1.SecretKeySelector {
+	selectors := []corev1.SecretKeySelector{}
+	if spec == nil {
+		return selectors
+	}
+
+	if spec.BasicAuth != nil {
+		selectors = append(selectors, spec.BasicAuth.Username, spec.BasicAuth.Password)
+	}
+	if spec.BearerToken != nil {
+		selectors = append(selectors, *spec.BearerToken)
+	}
+	if spec.TLS != nil {
+		for _, selector := range []*corev1.SecretKeySelector{spec.TLS.CA, spec.TLS.Cert, spec.TLS.Key} {
+			if selector != nil {
+				selectors = append(selectors, *selector)
+			}
+		}
+	}
+
+	return selectors
+}
@@ -0,0 +1,247 @@
+package monitoring
+
+import (
+	"context"
+
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/prometheus"
+)
+
+var _ = Describe("Remote write", func() {
+	common.ProcessConfig()
+
+	secretKey := func(name, key string) *corev1.SecretKeySelector {
+		return &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  key,
+		}
+	}
+
+	newMonitoring := func(spec *addonv1alpha1.MonitoringRemoteWriteSpec) *addonv1alpha1.Monitoring {
+		return &addonv1alpha1.Monitoring{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "test",
+			},
+			Spec: addonv1alpha1.MonitoringSpec{
+				RemoteWrite: spec,
+			},
+		}
+	}
+
+	getPrometheus := func(r *MonitoringReconciler, m *addonv1alpha1.Monitoring) *promv1.Prometheus {
+		Expect(r.reconcilePrometheus(context.TODO(), m)).To(Succeed())
+
+		p := &promv1.Prometheus{}
+		err := r.Get(context.TODO(), types.NamespacedName{
+			Name:      prometheusName,
+			Namespace: m.Namespace,
+		}, p)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		return p
+	}
+
+	getGaugeValue := func() float64 {
+		metric := &dto.Metric{}
+		Expect(RemoteWriteUp.Write(metric)).To(Succeed())
+		return metric.GetGauge().GetValue()
+	}
+
+	getFailures := func(reason string) float64 {
+		metric := &dto.Metric{}
+		Expect(RemoteWriteFailures.WithLabelValues(reason).Write(metric)).To(Succeed())
+		return metric.GetCounter().GetValue()
+	}
+
+	tokenSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "remote-write",
+			Namespace: "test",
+		},
+		Data: map[string][]byte{
+			"token": []byte("some-token"),
+		},
+	}
+
+	Context("when not configured", func() {
+		It("should not write to any endpoint", func() {
+			m := newMonitoring(nil)
+			r := newTestMonitoringReconciler(m)
+
+			Expect(getPrometheus(r, m).Spec.RemoteWrite).To(BeEmpty())
+
+			condition, err := r.getRemoteWriteCondition(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("NotConfigured"))
+		})
+	})
+
+	Context("when configured", func() {
+		It("should render the remote write of the addon Prometheus", func() {
+			m := newMonitoring(&addonv1alpha1.MonitoringRemoteWriteSpec{
+				URL:         "https://metrics.example.com/api/v1/write",
+				BearerToken: secretKey("remote-write", "token"),
+				TLS: &addonv1alpha1.MonitoringTLSConfig{
+					ServerName: "metrics.example.com",
+				},
+			})
+			r := newTestMonitoringReconciler(m, tokenSecret)
+
+			remoteWrite := getPrometheus(r, m).Spec.RemoteWrite
+			Expect(remoteWrite).To(HaveLen(1))
+			Expect(remoteWrite[0].URL).To(Equal("https://metrics.example.com/api/v1/write"))
+			Expect(remoteWrite[0].Authorization.Type).To(Equal("Bearer"))
+			Expect(remoteWrite[0].Authorization.Credentials).To(Equal(secretKey("remote-write", "token")))
+			Expect(remoteWrite[0].TLSConfig.ServerName).To(Equal("metrics.example.com"))
+			Expect(remoteWrite[0].WriteRelabelConfigs).To(ConsistOf(promv1.RelabelConfig{
+				Action:       "keep",
+				SourceLabels: []promv1.LabelName{"__name__"},
+				Regex:        "(DCGM_FI_.*|gpu_operator_.*)",
+			}))
+		})
+
+		It("should only write the allowlisted metrics", func() {
+			m := newMonitoring(&addonv1alpha1.MonitoringRemoteWriteSpec{
+				URL:              "https://metrics.example.com/api/v1/write",
+				MetricsAllowlist: []string{"DCGM_FI_DEV_GPU_UTIL", "DCGM_FI_DEV_FB_USED"},
+			})
+			r := newTestMonitoringReconciler(m)
+
+			remoteWrite := getPrometheus(r, m).Spec.RemoteWrite
+			Expect(remoteWrite[0].WriteRelabelConfigs[0].Regex).To(Equal("(DCGM_FI_DEV_GPU_UTIL|DCGM_FI_DEV_FB_USED)"))
+		})
+
+		It("should omit the remote write while its Secret is missing", func() {
+			m := newMonitoring(&addonv1alpha1.MonitoringRemoteWriteSpec{
+				URL:         "https://metrics.example.com/api/v1/write",
+				BearerToken: secretKey("remote-write", "token"),
+			})
+			r := newTestMonitoringReconciler(m)
+
+			Expect(getPrometheus(r, m).Spec.RemoteWrite).To(BeEmpty())
+
+			failures := getFailures("SecretMissing")
+			condition, err := r.getRemoteWriteCondition(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("SecretMissing"))
+			Expect(getFailures("SecretMissing")).To(Equal(failures + 1))
+		})
+	})
+
+	Context("with the remote storage metrics of the addon Prometheus", func() {
+		newRemoteWriteMonitoring := func() *addonv1alpha1.Monitoring {
+			return newMonitoring(&addonv1alpha1.MonitoringRemoteWriteSpec{
+				URL: "https://metrics.example.com/api/v1/write",
+			})
+		}
+
+		It("should report the samples sent without failures", func() {
+			server := newPrometheusStub(map[string]string{
+				"prometheus_remote_storage_samples_total":        `[{"metric":{},"value":[1660000000,"42"]}]`,
+				"prometheus_remote_storage_samples_failed_total": `[{"metric":{},"value":[1660000000,"0"]}]`,
+			})
+			defer server.Close()
+
+			m := newRemoteWriteMonitoring()
+			r := newTestMonitoringReconciler(m)
+			r.Prometheus = &testPrometheusQuerier{client: &prometheus.Client{Address: server.URL}}
+
+			condition, err := r.getRemoteWriteCondition(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("SamplesSent"))
+			Expect(getGaugeValue()).To(Equal(float64(1)))
+		})
+
+		It("should report the failing samples", func() {
+			server := newPrometheusStub(map[string]string{
+				"prometheus_remote_storage_samples_total":        `[{"metric":{},"value":[1660000000,"42"]}]`,
+				"prometheus_remote_storage_samples_failed_total": `[{"metric":{},"value":[1660000000,"42"]}]`,
+			})
+			defer server.Close()
+
+			m := newRemoteWriteMonitoring()
+			r := newTestMonitoringReconciler(m)
+			r.Prometheus = &testPrometheusQuerier{client: &prometheus.Client{Address: server.URL}}
+
+			failures := getFailures("SamplesFailing")
+			condition, err := r.getRemoteWriteCondition(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("SamplesFailing"))
+			Expect(condition.Message).To(ContainSubstring("https://metrics.example.com/api/v1/write"))
+			Expect(getGaugeValue()).To(Equal(float64(0)))
+			Expect(getFailures("SamplesFailing")).To(Equal(failures + 1))
+		})
+
+		It("should wait for the remote storage metrics", func() {
+			server := newPrometheusStub(map[string]string{
+				"prometheus_remote_storage_samples_total": `[]`,
+			})
+			defer server.Close()
+
+			m := newRemoteWriteMonitoring()
+			r := newTestMonitoringReconciler(m)
+			r.Prometheus = &testPrometheusQuerier{client: &prometheus.Client{Address: server.URL}}
+
+			condition, err := r.getRemoteWriteCondition(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("NoMetrics"))
+		})
+
+		It("should report the failed queries without failing the reconcile", func() {
+			server := newPrometheusStub(map[string]string{})
+			defer server.Close()
+
+			m := newRemoteWriteMonitoring()
+			r := newTestMonitoringReconciler(m)
+			r.Prometheus = &testPrometheusQuerier{client: &prometheus.Client{Address: server.URL}}
+
+			failures := getFailures("SamplesFailing")
+			condition, err := r.getRemoteWriteCondition(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("QueryFailed"))
+			Expect(getFailures("SamplesFailing")).To(Equal(failures))
+		})
+
+		It("should report the remote write condition without affecting the availability", func() {
+			server := newPrometheusStub(map[string]string{
+				"prometheus_remote_storage_samples_total":        `[{"metric":{},"value":[1660000000,"42"]}]`,
+				"prometheus_remote_storage_samples_failed_total": `[{"metric":{},"value":[1660000000,"42"]}]`,
+			})
+			defer server.Close()
+
+			m := newRemoteWriteMonitoring()
+			r := newTestMonitoringReconciler(m)
+			r.Prometheus = &testPrometheusQuerier{client: &prometheus.Client{Address: server.URL}}
+
+			res, err := r.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: m.Name, Namespace: m.Namespace},
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(res.RequeueAfter).ToNot(BeZero())
+
+			updated := &addonv1alpha1.Monitoring{}
+			Expect(r.Get(context.TODO(), types.NamespacedName{Name: m.Name, Namespace: m.Namespace}, updated)).To(Succeed())
+			Expect(meta.FindStatusCondition(updated.Status.Conditions, RemoteWriteCondition).Reason).To(Equal("SamplesFailing"))
+			Expect(meta.FindStatusCondition(updated.Status.Conditions, AvailableCondition).Message).ToNot(ContainSubstring(RemoteWriteCondition))
+		})
+	})
+})
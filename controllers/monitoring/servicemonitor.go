@@ -0,0 +1,360 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+const (
+	dcgmExporterServiceMonitorName = "gpuaddon-nvidia-dcgm-exporter"
+
+	nodeStatusExporterServiceMonitorName = "gpuaddon-nvidia-node-status-exporter"
+
+	controllerManagerServiceMonitorName = "gpuaddon-controller-manager"
+
+	prometheusServiceMonitorName = "gpuaddon-prometheus"
+
+	kubeStateMetricsServiceMonitorName = "gpuaddon-kube-state-metrics"
+
+	// kubeStateMetricsNamespace is the namespace of the kube-state-metrics
+	// of the OpenShift platform monitoring.
+	kubeStateMetricsNamespace = "openshift-monitoring"
+)
+
+// serviceMonitorTemplate describes a metrics endpoint scraped by the addon
+// Prometheus. Only the metrics matching keepMetrics are ingested to keep the
+// cardinality of the addon Prometheus bounded.
+type serviceMonitorTemplate struct {
+	name        string
+	selector    map[string]string
+	endpoint    promv1.Endpoint
+	keepMetrics string
+	dropLabels  string
+	// relabelings are applied to the kept metrics.
+	relabelings []*promv1.RelabelConfig
+}
+
+var (
+	// Services created by the GPU operator for its operands.
+	dcgmExporterServiceMonitor = serviceMonitorTemplate{
+		name: dcgmExporterServiceMonitorName,
+		selector: map[string]string{
+			"app": "nvidia-dcgm-exporter",
+		},
+		endpoint: promv1.Endpoint{
+			Port:   "gpu-metrics",
+			Path:   "/metrics",
+			Scheme: "http",
+		},
+		keepMetrics: "DCGM_FI_.*",
+		dropLabels:  "pci_bus_id|DCGM_FI_DRIVER_VERSION|DCGM_FI_PROCESS_NAME",
+	}
+
+	nodeStatusExporterServiceMonitor = serviceMonitorTemplate{
+		name: nodeStatusExporterServiceMonitorName,
+		selector: map[string]string{
+			"app": "nvidia-node-status-exporter",
+		},
+		endpoint: promv1.Endpoint{
+			Port:   "node-status",
+			Path:   "/metrics",
+			Scheme: "http",
+		},
+		keepMetrics: "gpu_operator_.*",
+	}
+
+	// The controller-manager metrics are served on :8080 behind the
+	// kube-rbac-proxy of controller-manager-metrics-service.
+	controllerManagerServiceMonitor = serviceMonitorTemplate{
+		name: controllerManagerServiceMonitorName,
+		selector: map[string]string{
+			"control-plane": "controller-manager",
+		},
+		endpoint: promv1.Endpoint{
+			Port:            "https",
+			Path:            "/metrics",
+			Scheme:          "https",
+			BearerTokenFile: "/var/run/secrets/kubernetes.io/serviceaccount/token",
+			TLSConfig: &promv1.TLSConfig{
+				SafeTLSConfig: promv1.SafeTLSConfig{
+					InsecureSkipVerify: true,
+				},
+			},
+		},
+		keepMetrics: "nvidia_gpuaddon_.*|controller_runtime_reconcile_.*|workqueue_(depth|adds_total|retries_total)",
+	}
+
+	// The addon Prometheus itself, behind its kube-rbac-proxy, for the
+	// remote storage metrics reporting the state of the remote write.
+	prometheusServiceMonitor = serviceMonitorTemplate{
+		name: prometheusServiceMonitorName,
+		selector: map[string]string{
+			"app": prometheusName,
+		},
+		endpoint: promv1.Endpoint{
+			Port:            "https",
+			Path:            "/metrics",
+			Scheme:          "https",
+			BearerTokenFile: "/var/run/secrets/kubernetes.io/serviceaccount/token",
+			TLSConfig: &promv1.TLSConfig{
+				SafeTLSConfig: promv1.SafeTLSConfig{
+					InsecureSkipVerify: true,
+				},
+			},
+		},
+		keepMetrics: "prometheus_remote_storage_.*",
+	}
+
+	// The pod GPU requests used for showback. The namespace and pod labels
+	// of kube-state-metrics are kept and only the series of the GPU requests,
+	// of the running pods and of the namespace labels are ingested.
+	kubeStateMetricsServiceMonitor = serviceMonitorTemplate{
+		name: kubeStateMetricsServiceMonitorName,
+		selector: map[string]string{
+			"app.kubernetes.io/name": "kube-state-metrics",
+		},
+		endpoint: promv1.Endpoint{
+			Port:            "https-main",
+			Path:            "/metrics",
+			Scheme:          "https",
+			HonorLabels:     true,
+			BearerTokenFile: "/var/run/secrets/kubernetes.io/serviceaccount/token",
+			TLSConfig: &promv1.TLSConfig{
+				SafeTLSConfig: promv1.SafeTLSConfig{
+					InsecureSkipVerify: true,
+				},
+			},
+		},
+		keepMetrics: "kube_pod_container_resource_requests|kube_pod_status_phase|kube_namespace_labels",
+		relabelings: []*promv1.RelabelConfig{
+			{
+				Action:       "keep",
+				SourceLabels: []promv1.LabelName{"__name__", "resource", "phase"},
+				Regex:        "kube_pod_container_resource_requests;nvidia_com_gpu;|kube_pod_status_phase;;Running|kube_namespace_labels;;",
+			},
+		},
+	}
+)
+
+// reconcileServiceMonitors creates the ServiceMonitors of the addon
+// Prometheus. The GPU operator operands are only monitored once the GPU
+// operator namespace is known, which is reported by the returned boolean.
+func (r *MonitoringReconciler) reconcileServiceMonitors(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring) (bool, error) {
+
+	logger := log.FromContext(ctx, "Reconcile Step", "ServiceMonitors")
+
+	if isUserWorkloadMode(m) {
+		if err := r.reconcileMetricsReaderTokenSecret(ctx, m); err != nil {
+			return false, err
+		}
+	} else {
+		if err := r.deleteMetricsReaderTokenSecret(ctx, m); err != nil {
+			return false, err
+		}
+	}
+
+	if err := r.reconcileServiceMonitor(ctx, m, controllerManagerServiceMonitor, m.Namespace); err != nil {
+		return false, err
+	}
+
+	// The user-workload Prometheus cannot scrape the platform monitoring, and
+	// there is no addon Prometheus in UserWorkload mode.
+	if isUserWorkloadMode(m) {
+		if err := r.deleteServiceMonitorsByName(ctx, m,
+			kubeStateMetricsServiceMonitorName,
+			prometheusServiceMonitorName); err != nil {
+			return false, err
+		}
+	} else {
+		if err := r.reconcileServiceMonitor(ctx, m, kubeStateMetricsServiceMonitor, kubeStateMetricsNamespace); err != nil {
+			return false, err
+		}
+		if err := r.reconcileServiceMonitor(ctx, m, prometheusServiceMonitor, m.Namespace); err != nil {
+			return false, err
+		}
+	}
+
+	gpuOperatorNamespace, err := r.getGPUOperatorNamespace(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if gpuOperatorNamespace == "" {
+		logger.Info("GPU operator namespace not reported by the ClusterPolicy yet, skipping its operands",
+			"clusterPolicy", common.GlobalConfig.ClusterPolicyName)
+		return false, nil
+	}
+
+	// The user-workload Prometheus only scrapes the Services of the namespace
+	// of a ServiceMonitor.
+	if isUserWorkloadMode(m) && gpuOperatorNamespace != m.Namespace {
+		logger.Info("GPU operator installed outside of the addon namespace, skipping its operands in UserWorkload mode",
+			"gpuOperatorNamespace", gpuOperatorNamespace)
+		return true, r.deleteServiceMonitorsByName(ctx, m,
+			dcgmExporterServiceMonitorName,
+			nodeStatusExporterServiceMonitorName)
+	}
+
+	for _, t := range []serviceMonitorTemplate{
+		dcgmExporterServiceMonitor,
+		nodeStatusExporterServiceMonitor,
+	} {
+		if err := r.reconcileServiceMonitor(ctx, m, t, gpuOperatorNamespace); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (r *MonitoringReconciler) reconcileServiceMonitor(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring,
+	t serviceMonitorTemplate,
+	targetNamespace string) error {
+
+	logger := log.FromContext(ctx, "Reconcile Step", "ServiceMonitor CR")
+
+	sm := &promv1.ServiceMonitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      t.name,
+			Namespace: m.Namespace,
+		},
+	}
+
+	res, err := controllerutil.CreateOrPatch(ctx, r.Client, sm, func() error {
+		return r.setDesiredServiceMonitor(r.Client, sm, t, targetNamespace, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("ServiceMonitor reconciled successfully",
+		"name", sm.Name,
+		"namespace", sm.Namespace,
+		"targetNamespace", targetNamespace,
+		"result", res)
+
+	return nil
+}
+
+func (r *MonitoringReconciler) setDesiredServiceMonitor(
+	c client.Client,
+	sm *promv1.ServiceMonitor,
+	t serviceMonitorTemplate,
+	targetNamespace string,
+	m *addonv1alpha1.Monitoring) error {
+
+	if sm == nil {
+		return errors.New("servicemonitor cannot be nil")
+	}
+
+	if sm.Labels == nil {
+		sm.Labels = map[string]string{}
+	}
+	// Selected by the addon Prometheus ServiceMonitor selector.
+	sm.Labels["app"] = t.name
+
+	endpoint := *t.endpoint.DeepCopy()
+	endpoint.MetricRelabelConfigs = []*promv1.RelabelConfig{
+		{
+			Action:       "keep",
+			SourceLabels: []promv1.LabelName{"__name__"},
+			Regex:        fmt.Sprintf("(%s)", t.keepMetrics),
+		},
+	}
+	if t.dropLabels != "" {
+		endpoint.MetricRelabelConfigs = append(endpoint.MetricRelabelConfigs, &promv1.RelabelConfig{
+			Action: "labeldrop",
+			Regex:  fmt.Sprintf("(%s)", t.dropLabels),
+		})
+	}
+	for _, relabeling := range t.relabelings {
+		endpoint.MetricRelabelConfigs = append(endpoint.MetricRelabelConfigs, relabeling.DeepCopy())
+	}
+
+	// The ServiceMonitors live in the addon namespace, next to the addon
+	// Prometheus, and select Services of the target namespace. The
+	// user-workload Prometheus ignores the namespace selector.
+	sm.Spec = promv1.ServiceMonitorSpec{
+		Selector: metav1.LabelSelector{
+			MatchLabels: t.selector,
+		},
+		Endpoints: []promv1.Endpoint{endpoint},
+	}
+	if isUserWorkloadMode(m) {
+		sm.Spec.Endpoints = []promv1.Endpoint{getUserWorkloadEndpoint(endpoint)}
+	} else {
+		sm.Spec.NamespaceSelector = promv1.NamespaceSelector{
+			MatchNames: []string{targetNamespace},
+		}
+	}
+
+	return ctrl.SetControllerReference(m, sm, c.Scheme())
+}
+
+// getGPUOperatorNamespace returns the namespace the GPU operator is installed
+// in, as reported by the ClusterPolicy, or an empty string when unknown yet.
+func (r *MonitoringReconciler) getGPUOperatorNamespace(ctx context.Context) (string, error) {
+	cp := &gpuv1.ClusterPolicy{}
+	if err := r.Get(ctx, client.ObjectKey{
+		Name: common.GlobalConfig.ClusterPolicyName,
+	}, cp); err != nil {
+		if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("unable to get ClusterPolicy %s: %w", common.GlobalConfig.ClusterPolicyName, err)
+	}
+
+	return cp.Status.Namespace, nil
+}
+
+func (r *MonitoringReconciler) deleteServiceMonitors(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring) error {
+
+	return r.deleteServiceMonitorsByName(ctx, m,
+		dcgmExporterServiceMonitorName,
+		nodeStatusExporterServiceMonitorName,
+		controllerManagerServiceMonitorName,
+		kubeStateMetricsServiceMonitorName,
+		prometheusServiceMonitorName)
+}
+
+func (r *MonitoringReconciler) deleteServiceMonitorsByName(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring,
+	names ...string) error {
+
+	for _, name := range names {
+		sm := &promv1.ServiceMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: m.Namespace,
+			},
+		}
+
+		err := r.Delete(ctx, sm)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete ServiceMonitor %s in %s: %w", sm.Name, sm.Namespace, err)
+		}
+	}
+
+	return nil
+}
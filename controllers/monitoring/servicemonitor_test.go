@@ -0,0 +1,126 @@
+package monitoring
+
+import (
+	"context"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+var _ = Describe("ServiceMonitors", func() {
+	common.ProcessConfig()
+
+	m := &addonv1alpha1.Monitoring{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+	}
+
+	getServiceMonitor := func(r *MonitoringReconciler, name string) (*promv1.ServiceMonitor, error) {
+		sm := &promv1.ServiceMonitor{}
+		err := r.Get(context.TODO(), types.NamespacedName{
+			Name:      name,
+			Namespace: m.Namespace,
+		}, sm)
+		return sm, err
+	}
+
+	Context("when the GPU operator namespace is not known yet", func() {
+		It("should only monitor the controller-manager", func() {
+			r := newTestMonitoringReconciler()
+
+			discovered, err := r.reconcileServiceMonitors(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(discovered).To(BeFalse())
+
+			sm, err := getServiceMonitor(r, "gpuaddon-controller-manager")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(sm.Labels).To(HaveKey("app"))
+			Expect(sm.Spec.NamespaceSelector.MatchNames).To(ConsistOf(m.Namespace))
+			Expect(sm.Spec.Endpoints).To(HaveLen(1))
+			Expect(sm.Spec.Endpoints[0].Port).To(Equal("https"))
+
+			_, err = getServiceMonitor(r, "gpuaddon-nvidia-dcgm-exporter")
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should monitor the remote write of the addon Prometheus", func() {
+			r := newTestMonitoringReconciler()
+
+			_, err := r.reconcileServiceMonitors(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			sm, err := getServiceMonitor(r, "gpuaddon-prometheus")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(sm.Spec.Selector.MatchLabels).To(HaveKeyWithValue("app", prometheusName))
+			Expect(sm.Spec.NamespaceSelector.MatchNames).To(ConsistOf(m.Namespace))
+			Expect(sm.Spec.Endpoints[0].Port).To(Equal("https"))
+			Expect(sm.Spec.Endpoints[0].MetricRelabelConfigs[0].Regex).To(Equal("(prometheus_remote_storage_.*)"))
+		})
+	})
+
+	Context("when the ClusterPolicy reports the GPU operator namespace", func() {
+		cp := &gpuv1.ClusterPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: common.GlobalConfig.ClusterPolicyName,
+			},
+			Status: gpuv1.ClusterPolicyStatus{
+				Namespace: "nvidia-gpu-operator",
+			},
+		}
+
+		It("should monitor the GPU operator operands in their namespace", func() {
+			r := newTestMonitoringReconciler(cp)
+
+			discovered, err := r.reconcileServiceMonitors(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(discovered).To(BeTrue())
+
+			for name, app := range map[string]string{
+				"gpuaddon-nvidia-dcgm-exporter":        "nvidia-dcgm-exporter",
+				"gpuaddon-nvidia-node-status-exporter": "nvidia-node-status-exporter",
+			} {
+				sm, err := getServiceMonitor(r, name)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(sm.Spec.Selector.MatchLabels).To(HaveKeyWithValue("app", app))
+				Expect(sm.Spec.NamespaceSelector.MatchNames).To(ConsistOf("nvidia-gpu-operator"))
+				Expect(sm.OwnerReferences).To(HaveLen(1))
+
+				relabelings := sm.Spec.Endpoints[0].MetricRelabelConfigs
+				Expect(relabelings).ToNot(BeEmpty())
+				Expect(relabelings[0].Action).To(Equal("keep"))
+				Expect(relabelings[0].SourceLabels).To(ConsistOf(promv1.LabelName("__name__")))
+			}
+		})
+
+		It("should delete the ServiceMonitors", func() {
+			r := newTestMonitoringReconciler(cp)
+
+			_, err := r.reconcileServiceMonitors(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(r.deleteServiceMonitors(context.TODO(), m)).To(Succeed())
+
+			for _, name := range []string{
+				"gpuaddon-controller-manager",
+				"gpuaddon-nvidia-dcgm-exporter",
+				"gpuaddon-nvidia-node-status-exporter",
+				"gpuaddon-prometheus",
+			} {
+				_, err := getServiceMonitor(r, name)
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			}
+		})
+	})
+})
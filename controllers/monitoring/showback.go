@@ -0,0 +1,440 @@
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+const (
+	showbackRuleGroupName = "nvidia-gpu-addon-showback.rules"
+
+	// showbackRuleInterval is the evaluation interval of the showback rules,
+	// each recorded sample standing for that much GPU time.
+	showbackRuleInterval = time.Minute
+
+	showbackAllocatedRecord           = "namespace:nvidia_gpuaddon_gpu_allocated:sum"
+	showbackUsedRecord                = "namespace:nvidia_gpuaddon_gpu_used:sum"
+	showbackLabelAllocatedRecord      = "namespace_label:nvidia_gpuaddon_gpu_allocated:sum"
+	showbackLabelUsedRecord           = "namespace_label:nvidia_gpuaddon_gpu_used:sum"
+	showbackReportConfigMapName       = "gpuaddon-showback-report"
+	showbackReportGeneratedAnnotation = "nvidia.addons.rh-ecosystem-edge.io/showback-generated-at"
+	showbackReportSpecAnnotation      = "nvidia.addons.rh-ecosystem-edge.io/showback-spec"
+
+	// showbackReportRetryInterval is how often the report generation is
+	// retried when the Prometheus queries fail.
+	showbackReportRetryInterval = 5 * time.Minute
+
+	ShowbackReportCondition = "ShowbackReport"
+)
+
+var invalidLabelNameChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// showbackReportEntry is the GPU time of a namespace over the report window.
+type showbackReportEntry struct {
+	Namespace         string  `json:"namespace"`
+	Label             string  `json:"label,omitempty"`
+	AllocatedGPUHours float64 `json:"allocated_gpu_hours"`
+	UsedGPUHours      float64 `json:"used_gpu_hours"`
+}
+
+type showbackReport struct {
+	GeneratedAt    metav1.Time           `json:"generated_at"`
+	Window         string                `json:"window"`
+	NamespaceLabel string                `json:"namespace_label,omitempty"`
+	Entries        []showbackReportEntry `json:"entries"`
+}
+
+// getNamespaceLabelName returns the name of a namespace label in the
+// kube_namespace_labels series of kube-state-metrics.
+func getNamespaceLabelName(label string) string {
+	return "label_" + invalidLabelNameChars.ReplaceAllString(label, "_")
+}
+
+// getShowbackRuleGroup generates the rules recording the GPUs allocated to
+// the running pods and the GPUs actually used, i.e. the sum of the GPU
+// utilization, per namespace.
+func getShowbackRuleGroup(spec addonv1alpha1.MonitoringShowbackSpec) promv1.RuleGroup {
+	group := promv1.RuleGroup{
+		Name:     showbackRuleGroupName,
+		Interval: model.Duration(showbackRuleInterval).String(),
+		Rules: []promv1.Rule{
+			{
+				Record: showbackAllocatedRecord,
+				Expr: intstr.FromString(
+					`sum by (namespace) (kube_pod_container_resource_requests{resource="nvidia_com_gpu"} ` +
+						`* on (namespace, pod) group_left () max by (namespace, pod) (kube_pod_status_phase{phase="Running"}))`),
+			},
+			{
+				// The DCGM exporter labels the GPUs with the namespace of the
+				// pod they are allocated to, renamed as they clash with the
+				// labels of the exporter target.
+				Record: showbackUsedRecord,
+				Expr: intstr.FromString(
+					`sum by (namespace) (label_replace(DCGM_FI_DEV_GPU_UTIL{exported_namespace!=""}, ` +
+						`"namespace", "$1", "exported_namespace", "(.+)")) / 100`),
+			},
+		},
+	}
+
+	if spec.NamespaceLabel == "" {
+		return group
+	}
+
+	label := getNamespaceLabelName(spec.NamespaceLabel)
+	for record, source := range map[string]string{
+		showbackLabelAllocatedRecord: showbackAllocatedRecord,
+		showbackLabelUsedRecord:      showbackUsedRecord,
+	} {
+		group.Rules = append(group.Rules, promv1.Rule{
+			Record: record,
+			Expr: intstr.FromString(fmt.Sprintf(
+				"sum by (namespace, %s) (%s * on (namespace) group_left (%s) max by (namespace, %s) (kube_namespace_labels))",
+				label, source, label, label)),
+		})
+	}
+	sort.Slice(group.Rules, func(i, j int) bool {
+		return group.Rules[i].Record < group.Rules[j].Record
+	})
+
+	return group
+}
+
+// reconcileShowbackReport writes the showback report once per interval. It
+// returns the condition of the report and when it is due again.
+func (r *MonitoringReconciler) reconcileShowbackReport(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring) (metav1.Condition, time.Duration, error) {
+
+	logger := log.FromContext(ctx, "Reconcile Step", "Showback report")
+
+	spec := m.Spec.Showback.Report
+	if spec == nil {
+		if err := r.deleteShowbackReport(ctx, m); err != nil {
+			return metav1.Condition{}, 0, err
+		}
+		return common.NewCondition(
+			ShowbackReportCondition,
+			metav1.ConditionFalse,
+			"NotConfigured",
+			"The showback report is not configured"), 0, nil
+	}
+
+	window, interval, err := getShowbackReportDurations(spec)
+	if err != nil {
+		return metav1.Condition{}, 0, err
+	}
+
+	existing := &corev1.ConfigMap{}
+	err = r.Get(ctx, types.NamespacedName{
+		Name:      showbackReportConfigMapName,
+		Namespace: m.Namespace,
+	}, existing)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return metav1.Condition{}, 0, fmt.Errorf("unable to get ConfigMap %s in %s: %w", showbackReportConfigMapName, m.Namespace, err)
+	}
+	if err == nil && existing.Annotations[showbackReportSpecAnnotation] == getShowbackReportSpecKey(m) {
+		generatedAt, err := time.Parse(time.RFC3339, existing.Annotations[showbackReportGeneratedAnnotation])
+		if err == nil && time.Since(generatedAt) < time.Duration(interval) {
+			return getShowbackReportConditionGenerated(m, window, generatedAt),
+				time.Until(generatedAt.Add(time.Duration(interval))), nil
+		}
+	}
+
+	if r.Prometheus == nil {
+		return common.NewCondition(
+			ShowbackReportCondition,
+			metav1.ConditionFalse,
+			"QuerierNotConfigured",
+			"No Prometheus querier is configured to generate the showback report"), 0, nil
+	}
+
+	now := time.Now()
+	report, err := r.getShowbackReport(ctx, m, window, now)
+	if err != nil {
+		logger.Info("Failed to query the GPU time, retrying later", "error", err.Error())
+		return common.NewCondition(
+			ShowbackReportCondition,
+			metav1.ConditionFalse,
+			"QueryFailed",
+			err.Error()), showbackReportRetryInterval, nil
+	}
+
+	key, data, err := renderShowbackReport(report, spec.Format)
+	if err != nil {
+		return metav1.Condition{}, 0, err
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      showbackReportConfigMapName,
+			Namespace: m.Namespace,
+		},
+	}
+
+	res, err := controllerutil.CreateOrPatch(ctx, r.Client, cm, func() error {
+		return r.setDesiredShowbackReport(r.Client, cm, key, data, now, m)
+	})
+	if err != nil {
+		return metav1.Condition{}, 0, err
+	}
+
+	logger.Info("Showback report generated successfully",
+		"name", cm.Name,
+		"namespace", cm.Namespace,
+		"entries", len(report.Entries),
+		"result", res)
+
+	return getShowbackReportConditionGenerated(m, window, now), time.Duration(interval), nil
+}
+
+func (r *MonitoringReconciler) setDesiredShowbackReport(
+	c client.Client,
+	cm *corev1.ConfigMap,
+	key string,
+	data string,
+	generatedAt time.Time,
+	m *addonv1alpha1.Monitoring) error {
+
+	if cm == nil {
+		return errors.New("configmap cannot be nil")
+	}
+
+	if cm.Annotations == nil {
+		cm.Annotations = map[string]string{}
+	}
+	cm.Annotations[showbackReportGeneratedAnnotation] = generatedAt.UTC().Format(time.RFC3339)
+	cm.Annotations[showbackReportSpecAnnotation] = getShowbackReportSpecKey(m)
+
+	cm.Data = map[string]string{
+		key: data,
+	}
+
+	return ctrl.SetControllerReference(m, cm, c.Scheme())
+}
+
+// getShowbackReport sums the recorded GPU time of each namespace over the
+// report window.
+func (r *MonitoringReconciler) getShowbackReport(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring,
+	window model.Duration,
+	now time.Time) (*showbackReport, error) {
+
+	spec := m.Spec.Showback
+	allocatedRecord, usedRecord := showbackAllocatedRecord, showbackUsedRecord
+	label := ""
+	if spec.NamespaceLabel != "" {
+		allocatedRecord, usedRecord = showbackLabelAllocatedRecord, showbackLabelUsedRecord
+		label = getNamespaceLabelName(spec.NamespaceLabel)
+	}
+
+	report := &showbackReport{
+		GeneratedAt:    metav1.NewTime(now.UTC()),
+		Window:         window.String(),
+		NamespaceLabel: spec.NamespaceLabel,
+		Entries:        []showbackReportEntry{},
+	}
+	entries := map[string]*showbackReportEntry{}
+
+	for _, record := range []string{allocatedRecord, usedRecord} {
+		query := fmt.Sprintf("sum_over_time(%s[%s]) * %g", record, window, showbackRuleInterval.Hours())
+
+		vector, err := r.Prometheus.Query(ctx, m, query, now)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, sample := range vector {
+			namespace := string(sample.Metric["namespace"])
+			value := string(sample.Metric[model.LabelName(label)])
+
+			key := namespace + "/" + value
+			entry, ok := entries[key]
+			if !ok {
+				entry = &showbackReportEntry{
+					Namespace: namespace,
+					Label:     value,
+				}
+				entries[key] = entry
+			}
+
+			if record == allocatedRecord {
+				entry.AllocatedGPUHours = float64(sample.Value)
+			} else {
+				entry.UsedGPUHours = float64(sample.Value)
+			}
+		}
+	}
+
+	for _, entry := range entries {
+		report.Entries = append(report.Entries, *entry)
+	}
+	sort.Slice(report.Entries, func(i, j int) bool {
+		if report.Entries[i].Namespace != report.Entries[j].Namespace {
+			return report.Entries[i].Namespace < report.Entries[j].Namespace
+		}
+		return report.Entries[i].Label < report.Entries[j].Label
+	})
+
+	return report, nil
+}
+
+// renderShowbackReport returns the ConfigMap key and content of the report.
+func renderShowbackReport(
+	report *showbackReport,
+	format addonv1alpha1.MonitoringShowbackReportFormat) (string, string, error) {
+
+	if format == addonv1alpha1.MonitoringShowbackReportFormatJSON {
+		raw, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return "", "", fmt.Errorf("failed to render the showback report: %w", err)
+		}
+		return "report.json", string(raw), nil
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	header := []string{"namespace"}
+	if report.NamespaceLabel != "" {
+		header = append(header, report.NamespaceLabel)
+	}
+	header = append(header, "allocated_gpu_hours", "used_gpu_hours")
+	_ = w.Write(header)
+
+	for _, entry := range report.Entries {
+		row := []string{entry.Namespace}
+		if report.NamespaceLabel != "" {
+			row = append(row, entry.Label)
+		}
+		row = append(row,
+			strconv.FormatFloat(entry.AllocatedGPUHours, 'f', 2, 64),
+			strconv.FormatFloat(entry.UsedGPUHours, 'f', 2, 64))
+		_ = w.Write(row)
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", "", fmt.Errorf("failed to render the showback report: %w", err)
+	}
+
+	return "report.csv", buf.String(), nil
+}
+
+func getShowbackReportDurations(spec *addonv1alpha1.MonitoringShowbackReportSpec) (model.Duration, model.Duration, error) {
+	window := addonv1alpha1.MonitoringDefaultShowbackReportWindow
+	if spec.Window != "" {
+		window = spec.Window
+	}
+
+	interval := addonv1alpha1.MonitoringDefaultShowbackReportInterval
+	if spec.Interval != "" {
+		interval = spec.Interval
+	}
+
+	windowDuration, err := model.ParseDuration(window)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid showback report window %q: %w", window, err)
+	}
+
+	intervalDuration, err := model.ParseDuration(interval)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid showback report interval %q: %w", interval, err)
+	}
+
+	return windowDuration, intervalDuration, nil
+}
+
+// getShowbackReportSpecKey identifies the settings a report was generated
+// with, so that it is generated again when they change.
+func getShowbackReportSpecKey(m *addonv1alpha1.Monitoring) string {
+	spec := m.Spec.Showback
+	return fmt.Sprintf("%s;%s;%s;%s", spec.Report.Window, spec.Report.Interval, spec.Report.Format, spec.NamespaceLabel)
+}
+
+// getShowbackRetention returns how long the addon Prometheus retains the
+// samples, or 0 if unknown, e.g. in UserWorkload mode.
+func getShowbackRetention(m *addonv1alpha1.Monitoring) model.Duration {
+	if isUserWorkloadMode(m) {
+		return 0
+	}
+
+	retentionTime := addonv1alpha1.MonitoringDefaultPrometheusRetentionTime
+	if m.Spec.Prometheus.RetentionTime != "" {
+		retentionTime = m.Spec.Prometheus.RetentionTime
+	}
+
+	retention, err := model.ParseDuration(retentionTime)
+	if err != nil {
+		return 0
+	}
+
+	return retention
+}
+
+// getShowbackReportConditionGenerated reports a generated report, warning
+// when the window exceeds the retention of Prometheus, the report then only
+// covering the retained samples.
+func getShowbackReportConditionGenerated(
+	m *addonv1alpha1.Monitoring,
+	window model.Duration,
+	generatedAt time.Time) metav1.Condition {
+
+	if retention := getShowbackRetention(m); retention > 0 && window > retention {
+		return common.NewCondition(
+			ShowbackReportCondition,
+			metav1.ConditionTrue,
+			"WindowExceedsRetention",
+			fmt.Sprintf("The showback report was generated at %s, but only covers the last %s of its %s window "+
+				"retained by Prometheus, spec.prometheus.retention_time should be raised",
+				generatedAt.UTC().Format(time.RFC3339), retention, window))
+	}
+
+	return common.NewCondition(
+		ShowbackReportCondition,
+		metav1.ConditionTrue,
+		"ReportGenerated",
+		fmt.Sprintf("The showback report was generated at %s", generatedAt.UTC().Format(time.RFC3339)))
+}
+
+func (r *MonitoringReconciler) deleteShowbackReport(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring) error {
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      showbackReportConfigMapName,
+			Namespace: m.Namespace,
+		},
+	}
+
+	err := r.Delete(ctx, cm)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete ConfigMap %s in %s: %w", cm.Name, cm.Namespace, err)
+	}
+
+	return nil
+}
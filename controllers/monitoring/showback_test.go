@@ -0,0 +1,273 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/prometheus"
+)
+
+// testPrometheusQuerier queries a stubbed Prometheus HTTP API.
+type testPrometheusQuerier struct {
+	client *prometheus.Client
+}
+
+func (q *testPrometheusQuerier) Query(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring,
+	query string,
+	ts time.Time) (model.Vector, error) {
+
+	return q.client.Query(ctx, query, ts)
+}
+
+// newPrometheusStub serves the vector of the first key found in each query,
+// failing the queries without one.
+func newPrometheusStub(results map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query().Get("query")
+		for key, result := range results {
+			if strings.Contains(query, key) {
+				fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":%s}}`, result)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"status":"error","errorType":"bad_data","error":"unexpected query"}`)
+	}))
+}
+
+var _ = Describe("Showback", func() {
+	common.ProcessConfig()
+
+	newMonitoring := func(showback addonv1alpha1.MonitoringShowbackSpec) *addonv1alpha1.Monitoring {
+		return &addonv1alpha1.Monitoring{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "test",
+			},
+			Spec: addonv1alpha1.MonitoringSpec{
+				Showback: showback,
+			},
+		}
+	}
+
+	getReport := func(r *MonitoringReconciler, m *addonv1alpha1.Monitoring) *corev1.ConfigMap {
+		cm := &corev1.ConfigMap{}
+		err := r.Get(context.TODO(), types.NamespacedName{
+			Name:      showbackReportConfigMapName,
+			Namespace: m.Namespace,
+		}, cm)
+		Expect(err).ShouldNot(HaveOccurred())
+		return cm
+	}
+
+	Context("Recording rules", func() {
+		It("should record the GPU time per namespace", func() {
+			group := getShowbackRuleGroup(addonv1alpha1.MonitoringShowbackSpec{})
+
+			Expect(group.Interval).To(Equal("1m"))
+			Expect(group.Rules).To(HaveLen(2))
+			Expect(group.Rules[0].Record).To(Equal(showbackAllocatedRecord))
+			Expect(group.Rules[0].Expr.String()).To(ContainSubstring(`resource="nvidia_com_gpu"`))
+			Expect(group.Rules[1].Record).To(Equal(showbackUsedRecord))
+			Expect(group.Rules[1].Expr.String()).To(ContainSubstring("DCGM_FI_DEV_GPU_UTIL"))
+		})
+
+		It("should aggregate the GPU time by namespace label", func() {
+			group := getShowbackRuleGroup(addonv1alpha1.MonitoringShowbackSpec{
+				NamespaceLabel: "example.com/cost-center",
+			})
+
+			Expect(group.Rules).To(HaveLen(4))
+			for _, rule := range group.Rules {
+				if strings.HasPrefix(rule.Record, "namespace_label:") {
+					Expect(rule.Expr.String()).To(ContainSubstring("label_example_com_cost_center"))
+					Expect(rule.Expr.String()).To(ContainSubstring("kube_namespace_labels"))
+				}
+			}
+		})
+	})
+
+	Context("Report", func() {
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			server = newPrometheusStub(map[string]string{
+				showbackAllocatedRecord: `[
+					{"metric":{"namespace":"team-b"},"value":[1660000000,"48"]},
+					{"metric":{"namespace":"team-a"},"value":[1660000000,"12.5"]}
+				]`,
+				showbackUsedRecord: `[
+					{"metric":{"namespace":"team-a"},"value":[1660000000,"3.25"]}
+				]`,
+			})
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should not generate a report unless configured", func() {
+			m := newMonitoring(addonv1alpha1.MonitoringShowbackSpec{})
+			r := newTestMonitoringReconciler(m)
+
+			condition, next, err := r.reconcileShowbackReport(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(next).To(BeZero())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("NotConfigured"))
+		})
+
+		It("should write a CSV report of the GPU hours", func() {
+			m := newMonitoring(addonv1alpha1.MonitoringShowbackSpec{
+				Report: &addonv1alpha1.MonitoringShowbackReportSpec{},
+			})
+			r := newTestMonitoringReconciler(m)
+			r.Prometheus = &testPrometheusQuerier{client: &prometheus.Client{Address: server.URL}}
+
+			condition, next, err := r.reconcileShowbackReport(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(next).To(Equal(24 * time.Hour))
+
+			cm := getReport(r, m)
+			Expect(cm.Annotations).To(HaveKey(showbackReportGeneratedAnnotation))
+			Expect(cm.Data).To(HaveKeyWithValue("report.csv",
+				"namespace,allocated_gpu_hours,used_gpu_hours\n"+
+					"team-a,12.50,3.25\n"+
+					"team-b,48.00,0.00\n"))
+		})
+
+		It("should write a JSON report by namespace label", func() {
+			server.Close()
+			server = newPrometheusStub(map[string]string{
+				showbackLabelAllocatedRecord: `[
+					{"metric":{"namespace":"team-a","label_cost_center":"cc-1"},"value":[1660000000,"10"]}
+				]`,
+				showbackLabelUsedRecord: `[]`,
+			})
+
+			m := newMonitoring(addonv1alpha1.MonitoringShowbackSpec{
+				NamespaceLabel: "cost-center",
+				Report: &addonv1alpha1.MonitoringShowbackReportSpec{
+					Window: "7d",
+					Format: addonv1alpha1.MonitoringShowbackReportFormatJSON,
+				},
+			})
+			r := newTestMonitoringReconciler(m)
+			r.Prometheus = &testPrometheusQuerier{client: &prometheus.Client{Address: server.URL}}
+
+			_, _, err := r.reconcileShowbackReport(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			cm := getReport(r, m)
+			Expect(cm.Data).To(HaveKey("report.json"))
+
+			report := &showbackReport{}
+			Expect(json.Unmarshal([]byte(cm.Data["report.json"]), report)).To(Succeed())
+			Expect(report.Window).To(Equal("1w"))
+			Expect(report.NamespaceLabel).To(Equal("cost-center"))
+			Expect(report.Entries).To(Equal([]showbackReportEntry{{
+				Namespace:         "team-a",
+				Label:             "cc-1",
+				AllocatedGPUHours: 10,
+			}}))
+		})
+
+		It("should not regenerate the report before the interval elapsed", func() {
+			m := newMonitoring(addonv1alpha1.MonitoringShowbackSpec{
+				Report: &addonv1alpha1.MonitoringShowbackReportSpec{},
+			})
+			m.Spec.Prometheus.RetentionTime = "30d"
+			r := newTestMonitoringReconciler(m)
+			r.Prometheus = &testPrometheusQuerier{client: &prometheus.Client{Address: server.URL}}
+
+			_, _, err := r.reconcileShowbackReport(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			// Queries would fail from now on.
+			server.Close()
+
+			condition, next, err := r.reconcileShowbackReport(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(condition.Reason).To(Equal("ReportGenerated"))
+			Expect(next).To(BeNumerically("<=", 24*time.Hour))
+			Expect(next).To(BeNumerically(">", 23*time.Hour))
+		})
+
+		It("should warn when the window exceeds the Prometheus retention", func() {
+			m := newMonitoring(addonv1alpha1.MonitoringShowbackSpec{
+				Report: &addonv1alpha1.MonitoringShowbackReportSpec{Window: "7d"},
+			})
+			m.Spec.Prometheus.RetentionTime = "2d"
+			r := newTestMonitoringReconciler(m)
+			r.Prometheus = &testPrometheusQuerier{client: &prometheus.Client{Address: server.URL}}
+
+			condition, _, err := r.reconcileShowbackReport(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("WindowExceedsRetention"))
+			Expect(condition.Message).To(ContainSubstring("only covers the last 2d of its 1w window"))
+			getReport(r, m)
+
+			By("not knowing the retention of the OpenShift Prometheus in UserWorkload mode")
+			m.Spec.Mode = addonv1alpha1.MonitoringModeUserWorkload
+			Expect(getShowbackReportConditionGenerated(m, model.Duration(7*24*time.Hour), time.Now()).Reason).
+				To(Equal("ReportGenerated"))
+		})
+
+		It("should retry when Prometheus cannot be queried", func() {
+			server.Close()
+
+			m := newMonitoring(addonv1alpha1.MonitoringShowbackSpec{
+				Report: &addonv1alpha1.MonitoringShowbackReportSpec{},
+			})
+			r := newTestMonitoringReconciler(m)
+			r.Prometheus = &testPrometheusQuerier{client: &prometheus.Client{Address: server.URL}}
+
+			condition, next, err := r.reconcileShowbackReport(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("QueryFailed"))
+			Expect(next).To(Equal(showbackReportRetryInterval))
+		})
+
+		It("should delete the report once unset", func() {
+			m := newMonitoring(addonv1alpha1.MonitoringShowbackSpec{
+				Report: &addonv1alpha1.MonitoringShowbackReportSpec{},
+			})
+			r := newTestMonitoringReconciler(m)
+			r.Prometheus = &testPrometheusQuerier{client: &prometheus.Client{Address: server.URL}}
+
+			_, _, err := r.reconcileShowbackReport(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+			getReport(r, m)
+
+			m.Spec.Showback.Report = nil
+			_, _, err = r.reconcileShowbackReport(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = r.Get(context.TODO(), types.NamespacedName{
+				Name:      showbackReportConfigMapName,
+				Namespace: m.Namespace,
+			}, &corev1.ConfigMap{})
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,199 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+const (
+	KubeRBACProxyConfigCondition = "KubeRBACProxyConfig"
+	PrometheusServiceCondition   = "PrometheusService"
+	PrometheusCondition          = "Prometheus"
+	PrometheusRuleCondition      = "PrometheusRule"
+	ServiceMonitorsCondition     = "ServiceMonitors"
+	AlertmanagerCondition        = "Alertmanager"
+	AlertmanagerConfigCondition  = "AlertmanagerConfig"
+
+	// RemoteWriteCondition reports whether the addon Prometheus sends the
+	// samples to the remote write endpoint without failures. It does not
+	// affect the Available condition.
+	RemoteWriteCondition = "RemoteWrite"
+
+	// ReceiverNotConfiguredCondition is True while built-in alert receivers
+	// are omitted because their Secret is missing or incomplete.
+	ReceiverNotConfiguredCondition = "ReceiverNotConfigured"
+
+	// receiverNotConfiguredReason marks the conditions of components that
+	// depend on a receiver which is not configured. They do not affect the
+	// availability of the monitoring stack.
+	receiverNotConfiguredReason = "ReceiverNotConfigured"
+
+	AvailableCondition = "Available"
+	DegradedCondition  = "Degraded"
+
+	// readinessCheckInterval is how often the Prometheus and Alertmanager
+	// StatefulSets, which are not owned by the Monitoring CR, are checked
+	// until all their replicas are ready.
+	readinessCheckInterval = 30 * time.Second
+)
+
+func getStepConditionReconciled(conditionType string) metav1.Condition {
+	return common.NewCondition(
+		conditionType,
+		metav1.ConditionTrue,
+		"Reconciled",
+		fmt.Sprintf("%s reconciled successfully", conditionType))
+}
+
+func getStepConditionFailed(conditionType string, err error) metav1.Condition {
+	return common.NewCondition(
+		conditionType,
+		metav1.ConditionFalse,
+		"ReconcileFailed",
+		err.Error())
+}
+
+// getStatefulSetCondition reports whether all the replicas of the StatefulSet
+// generated by the prometheus-operator for a component are ready.
+func (r *MonitoringReconciler) getStatefulSetCondition(
+	ctx context.Context,
+	conditionType string,
+	namespace string,
+	name string) (metav1.Condition, error) {
+
+	sts := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      name,
+		Namespace: namespace,
+	}, sts); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return common.NewCondition(
+				conditionType,
+				metav1.ConditionFalse,
+				"StatefulSetNotFound",
+				fmt.Sprintf("StatefulSet %s has not been created yet", name)), nil
+		}
+		return metav1.Condition{}, fmt.Errorf("unable to get StatefulSet %s in %s: %w", name, namespace, err)
+	}
+
+	desired := int32(1)
+	if sts.Spec.Replicas != nil {
+		desired = *sts.Spec.Replicas
+	}
+
+	message := fmt.Sprintf("StatefulSet %s has %d/%d ready replicas", name, sts.Status.ReadyReplicas, desired)
+
+	if sts.Status.ReadyReplicas < desired {
+		return common.NewCondition(
+			conditionType,
+			metav1.ConditionFalse,
+			"NotReady",
+			message), nil
+	}
+
+	return common.NewCondition(
+		conditionType,
+		metav1.ConditionTrue,
+		"Ready",
+		message), nil
+}
+
+func getReceiverNotConfiguredCondition(notConfigured map[string]string) metav1.Condition {
+	if len(notConfigured) == 0 {
+		return common.NewCondition(
+			ReceiverNotConfiguredCondition,
+			metav1.ConditionFalse,
+			"ReceiversConfigured",
+			"All the alert receivers are configured")
+	}
+
+	reasons := make([]string, 0, len(notConfigured))
+	for name, reason := range notConfigured {
+		reasons = append(reasons, fmt.Sprintf("%s: %s", name, reason))
+	}
+	sort.Strings(reasons)
+
+	return common.NewCondition(
+		ReceiverNotConfiguredCondition,
+		metav1.ConditionTrue,
+		"SecretMissing",
+		strings.Join(reasons, "; "))
+}
+
+func getAvailableCondition(conditions []metav1.Condition) metav1.Condition {
+	notReady := []string{}
+	for _, condition := range conditions {
+		if condition.Status != metav1.ConditionTrue && condition.Reason != receiverNotConfiguredReason {
+			notReady = append(notReady, condition.Type)
+		}
+	}
+
+	if len(notReady) > 0 {
+		return common.NewCondition(
+			AvailableCondition,
+			metav1.ConditionFalse,
+			"ComponentsNotReady",
+			fmt.Sprintf("Not ready: %s", strings.Join(notReady, ", ")))
+	}
+
+	return common.NewCondition(
+		AvailableCondition,
+		metav1.ConditionTrue,
+		"ComponentsReady",
+		"The monitoring stack is available")
+}
+
+func getDegradedConditionFailed(conditionType string, err error) metav1.Condition {
+	return common.NewCondition(
+		DegradedCondition,
+		metav1.ConditionTrue,
+		fmt.Sprintf("%sFailed", conditionType),
+		err.Error())
+}
+
+func getDegradedConditionSuccess() metav1.Condition {
+	return common.NewCondition(
+		DegradedCondition,
+		metav1.ConditionFalse,
+		"ReconcileSucceeded",
+		"The monitoring stack reconciled successfully")
+}
+
+// patchStatus merges the given conditions into the Monitoring status and
+// removes the stale ones, i.e. those of components not used in the current
+// mode. The reconcile error, if any, is returned unchanged so that it is
+// retried.
+func (r *MonitoringReconciler) patchStatus(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring,
+	conditions []metav1.Condition,
+	staleConditions []string,
+	err error) error {
+
+	patch := client.MergeFrom(m.DeepCopy())
+	for _, condition := range conditions {
+		meta.SetStatusCondition(&m.Status.Conditions, condition)
+	}
+	for _, conditionType := range staleConditions {
+		meta.RemoveStatusCondition(&m.Status.Conditions, conditionType)
+	}
+
+	if patchErr := r.Status().Patch(ctx, m, patch); patchErr != nil {
+		return fmt.Errorf("failed to patch status: %w", patchErr)
+	}
+
+	return err
+}
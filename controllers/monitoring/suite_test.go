@@ -8,6 +8,10 @@ import (
 )
 
 func TestSuite(t *testing.T) {
+	// Set by the operator Deployment.
+	t.Setenv("RELATED_IMAGE_KUBE_RBAC_PROXY_4_9", "registry.example.com/kube-rbac-proxy@sha256:4009")
+	t.Setenv("RELATED_IMAGE_KUBE_RBAC_PROXY_4_10", "registry.example.com/kube-rbac-proxy@sha256:4010")
+
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Monitoring Suite")
 }
@@ -0,0 +1,161 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	promv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+// UninstallingCondition is True while the resources owned by a Monitoring CR
+// marked for deletion are being removed.
+const UninstallingCondition = "Uninstalling"
+
+// teardownStep deletes the resources of a monitoring component, which are
+// gone once none of its objects can be found anymore.
+type teardownStep struct {
+	resource string
+	objects  []client.Object
+	delete   func(context.Context, *addonv1alpha1.Monitoring) error
+}
+
+// getTeardownSteps returns the steps removing the monitoring stack. Alerts
+// stop being routed first, so that the removal of the scrape targets and
+// rules does not page anyone.
+func (r *MonitoringReconciler) getTeardownSteps(m *addonv1alpha1.Monitoring) []teardownStep {
+	objectMeta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:      name,
+			Namespace: m.Namespace,
+		}
+	}
+
+	return []teardownStep{
+		{
+			resource: alertManagerConfigName,
+			objects:  []client.Object{&promv1alpha1.AlertmanagerConfig{ObjectMeta: objectMeta(alertManagerConfigName)}},
+			delete:   r.deleteAlertManagerConfig,
+		},
+		{
+			resource: alertManagerName,
+			objects:  []client.Object{&promv1.Alertmanager{ObjectMeta: objectMeta(alertManagerName)}},
+			delete:   r.deleteAlertManager,
+		},
+		{
+			resource: "ServiceMonitors",
+			objects: []client.Object{
+				&promv1.ServiceMonitor{ObjectMeta: objectMeta(dcgmExporterServiceMonitorName)},
+				&promv1.ServiceMonitor{ObjectMeta: objectMeta(nodeStatusExporterServiceMonitorName)},
+				&promv1.ServiceMonitor{ObjectMeta: objectMeta(controllerManagerServiceMonitorName)},
+				&promv1.ServiceMonitor{ObjectMeta: objectMeta(kubeStateMetricsServiceMonitorName)},
+				&promv1.ServiceMonitor{ObjectMeta: objectMeta(prometheusServiceMonitorName)},
+			},
+			delete: r.deleteServiceMonitors,
+		},
+		{
+			resource: metricsReaderTokenSecretName,
+			objects:  []client.Object{&corev1.Secret{ObjectMeta: objectMeta(metricsReaderTokenSecretName)}},
+			delete:   r.deleteMetricsReaderTokenSecret,
+		},
+		{
+			resource: prometheusRuleName,
+			objects:  []client.Object{&promv1.PrometheusRule{ObjectMeta: objectMeta(prometheusRuleName)}},
+			delete:   r.deletePrometheusRule,
+		},
+		{
+			resource: prometheusName,
+			objects:  []client.Object{&promv1.Prometheus{ObjectMeta: objectMeta(prometheusName)}},
+			delete:   r.deletePrometheus,
+		},
+		{
+			resource: prometheusServiceName,
+			objects:  []client.Object{&corev1.Service{ObjectMeta: objectMeta(prometheusServiceName)}},
+			delete:   r.deletePrometheusService,
+		},
+		{
+			resource: prometheusKubeRBACProxyConfigMapName,
+			objects:  []client.Object{&corev1.ConfigMap{ObjectMeta: objectMeta(prometheusKubeRBACProxyConfigMapName)}},
+			delete:   r.deletePrometheusKubeRBACProxyConfigMap,
+		},
+		{
+			resource: showbackReportConfigMapName,
+			objects:  []client.Object{&corev1.ConfigMap{ObjectMeta: objectMeta(showbackReportConfigMapName)}},
+			delete:   r.deleteShowbackReport,
+		},
+	}
+}
+
+// removeOwnedResources deletes the monitoring stack in order, each step
+// waiting for the resources of the previous one to be gone. It returns the
+// resource still being deleted, if any, so that the teardown is retried.
+func (r *MonitoringReconciler) removeOwnedResources(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring) (string, error) {
+
+	logger := log.FromContext(ctx, "Reconcile Step", "Teardown")
+
+	for _, step := range r.getTeardownSteps(m) {
+		if err := step.delete(ctx, m); err != nil {
+			return step.resource, err
+		}
+
+		for _, obj := range step.objects {
+			err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return step.resource, fmt.Errorf("unable to check the deletion of %s in %s: %w", obj.GetName(), obj.GetNamespace(), err)
+			}
+
+			logger.Info("Waiting for the deletion of a monitoring resource",
+				"resource", step.resource,
+				"name", obj.GetName(),
+				"namespace", obj.GetNamespace())
+
+			return step.resource, fmt.Errorf("%s in %s has not been deleted yet", obj.GetName(), obj.GetNamespace())
+		}
+	}
+
+	return "", nil
+}
+
+func (r *MonitoringReconciler) registerFinalizerIfNeeded(ctx context.Context, m *addonv1alpha1.Monitoring) error {
+	if controllerutil.ContainsFinalizer(m, common.GlobalConfig.AddonID) {
+		return nil
+	}
+
+	controllerutil.AddFinalizer(m, common.GlobalConfig.AddonID)
+
+	if err := r.Update(ctx, m); err != nil {
+		return fmt.Errorf("failed to add finalizer: %w", err)
+	}
+
+	return nil
+}
+
+func getUninstallingCondition(resource string, err error) metav1.Condition {
+	return common.NewCondition(
+		UninstallingCondition,
+		metav1.ConditionTrue,
+		"WaitingForDeletion",
+		fmt.Sprintf("Removing %s: %s", resource, err))
+}
+
+func getAvailableConditionUninstalling() metav1.Condition {
+	return common.NewCondition(
+		AvailableCondition,
+		metav1.ConditionFalse,
+		"Uninstalling",
+		"The monitoring stack is being removed")
+}
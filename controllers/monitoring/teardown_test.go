@@ -0,0 +1,149 @@
+package monitoring
+
+import (
+	"context"
+
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	promv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+var _ = Describe("Monitoring teardown", func() {
+	common.ProcessConfig()
+
+	// blockingFinalizer stands for the finalizer of another controller which
+	// has not released a resource yet.
+	const blockingFinalizer = "test/blocking"
+
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "test",
+			Namespace: "test",
+		},
+	}
+
+	exists := func(r *MonitoringReconciler, obj client.Object) bool {
+		err := r.Get(context.TODO(), client.ObjectKeyFromObject(obj), obj)
+		if k8serrors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).ShouldNot(HaveOccurred())
+		return true
+	}
+
+	// deployMonitoring reconciles a Monitoring CR and marks it for deletion.
+	deployMonitoring := func() (*MonitoringReconciler, *addonv1alpha1.Monitoring) {
+		m := &addonv1alpha1.Monitoring{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      req.Name,
+				Namespace: req.Namespace,
+			},
+		}
+		r := newTestMonitoringReconciler(m)
+
+		_, err := r.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(r.Get(context.TODO(), req.NamespacedName, m)).To(Succeed())
+		Expect(controllerutil.ContainsFinalizer(m, common.GlobalConfig.AddonID)).To(BeTrue())
+
+		return r, m
+	}
+
+	block := func(r *MonitoringReconciler, obj client.Object) {
+		Expect(r.Get(context.TODO(), client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+		controllerutil.AddFinalizer(obj, blockingFinalizer)
+		Expect(r.Update(context.TODO(), obj)).To(Succeed())
+	}
+
+	release := func(r *MonitoringReconciler, obj client.Object) {
+		Expect(r.Get(context.TODO(), client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+		controllerutil.RemoveFinalizer(obj, blockingFinalizer)
+		Expect(r.Update(context.TODO(), obj)).To(Succeed())
+	}
+
+	It("should register its finalizer on the Monitoring CR", func() {
+		deployMonitoring()
+	})
+
+	It("should remove the resources and the finalizer once they are gone", func() {
+		r, m := deployMonitoring()
+		Expect(r.Delete(context.TODO(), m)).To(Succeed())
+
+		_, err := r.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(exists(r, &promv1.Prometheus{ObjectMeta: metav1.ObjectMeta{Name: prometheusName, Namespace: m.Namespace}})).To(BeFalse())
+		Expect(exists(r, &promv1.Alertmanager{ObjectMeta: metav1.ObjectMeta{Name: alertManagerName, Namespace: m.Namespace}})).To(BeFalse())
+		Expect(exists(r, &addonv1alpha1.Monitoring{ObjectMeta: m.ObjectMeta})).To(BeFalse())
+	})
+
+	It("should wait for a resource to be gone before removing the next ones", func() {
+		r, m := deployMonitoring()
+
+		prometheus := &promv1.Prometheus{ObjectMeta: metav1.ObjectMeta{Name: prometheusName, Namespace: m.Namespace}}
+		block(r, prometheus)
+		Expect(r.Delete(context.TODO(), m)).To(Succeed())
+
+		_, err := r.Reconcile(context.TODO(), req)
+		Expect(err).Should(HaveOccurred())
+
+		// The steps before the Prometheus are done, those after it are not.
+		Expect(exists(r, &promv1alpha1.AlertmanagerConfig{ObjectMeta: metav1.ObjectMeta{Name: alertManagerConfigName, Namespace: m.Namespace}})).To(BeFalse())
+		Expect(exists(r, &promv1.Alertmanager{ObjectMeta: metav1.ObjectMeta{Name: alertManagerName, Namespace: m.Namespace}})).To(BeFalse())
+		Expect(exists(r, &promv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: prometheusRuleName, Namespace: m.Namespace}})).To(BeFalse())
+		Expect(exists(r, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: prometheusServiceName, Namespace: m.Namespace}})).To(BeTrue())
+		Expect(exists(r, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: prometheusKubeRBACProxyConfigMapName, Namespace: m.Namespace}})).To(BeTrue())
+
+		updated := &addonv1alpha1.Monitoring{}
+		Expect(r.Get(context.TODO(), req.NamespacedName, updated)).To(Succeed())
+		Expect(controllerutil.ContainsFinalizer(updated, common.GlobalConfig.AddonID)).To(BeTrue())
+
+		condition := meta.FindStatusCondition(updated.Status.Conditions, UninstallingCondition)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring(prometheusName))
+		Expect(meta.FindStatusCondition(updated.Status.Conditions, AvailableCondition).Reason).To(Equal("Uninstalling"))
+
+		// Retrying while the Prometheus is still there changes nothing.
+		_, err = r.Reconcile(context.TODO(), req)
+		Expect(err).Should(HaveOccurred())
+		Expect(exists(r, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: prometheusServiceName, Namespace: m.Namespace}})).To(BeTrue())
+
+		release(r, prometheus)
+
+		_, err = r.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(exists(r, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: prometheusServiceName, Namespace: m.Namespace}})).To(BeFalse())
+		Expect(exists(r, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: prometheusKubeRBACProxyConfigMapName, Namespace: m.Namespace}})).To(BeFalse())
+		Expect(exists(r, &addonv1alpha1.Monitoring{ObjectMeta: m.ObjectMeta})).To(BeFalse())
+	})
+
+	It("should not tear down a Monitoring CR without its finalizer", func() {
+		r, m := deployMonitoring()
+
+		controllerutil.AddFinalizer(m, blockingFinalizer)
+		controllerutil.RemoveFinalizer(m, common.GlobalConfig.AddonID)
+		Expect(r.Update(context.TODO(), m)).To(Succeed())
+		Expect(r.Delete(context.TODO(), m)).To(Succeed())
+
+		_, err := r.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(exists(r, &promv1.Prometheus{ObjectMeta: metav1.ObjectMeta{Name: prometheusName, Namespace: m.Namespace}})).To(BeTrue())
+	})
+})
@@ -0,0 +1,126 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+)
+
+const (
+	// metricsReaderTokenSecretName is the token of the prometheus-k8s
+	// ServiceAccount, which is granted the metrics-reader ClusterRole. The
+	// user-workload Prometheus denies access to its own filesystem, so it
+	// authenticates against the controller-manager kube-rbac-proxy with it.
+	metricsReaderTokenSecretName = "gpuaddon-metrics-reader-token"
+
+	metricsReaderServiceAccountName = "prometheus-k8s"
+
+	metricsReaderTokenKey = "token"
+
+	// ruleEvaluationScopeLabel makes the PrometheusRule evaluated by the
+	// user-workload Prometheus instead of the Thanos Ruler, as the addon
+	// alerts only depend on metrics scraped from the addon namespace.
+	ruleEvaluationScopeLabel = "openshift.io/prometheus-rule-evaluation-scope"
+	ruleEvaluationScopeLeaf  = "leaf-prometheus"
+)
+
+func isUserWorkloadMode(m *addonv1alpha1.Monitoring) bool {
+	return m.Spec.Mode == addonv1alpha1.MonitoringModeUserWorkload
+}
+
+func (r *MonitoringReconciler) reconcileMetricsReaderTokenSecret(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring) error {
+
+	logger := log.FromContext(ctx, "Reconcile Step", "Metrics reader token Secret")
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      metricsReaderTokenSecretName,
+			Namespace: m.Namespace,
+		},
+	}
+
+	res, err := controllerutil.CreateOrPatch(ctx, r.Client, secret, func() error {
+		return r.setDesiredMetricsReaderTokenSecret(r.Client, secret, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Metrics reader token Secret reconciled successfully",
+		"name", secret.Name,
+		"namespace", secret.Namespace,
+		"result", res)
+
+	return nil
+}
+
+func (r *MonitoringReconciler) setDesiredMetricsReaderTokenSecret(
+	c client.Client,
+	secret *corev1.Secret,
+	m *addonv1alpha1.Monitoring) error {
+
+	if secret == nil {
+		return errors.New("secret cannot be nil")
+	}
+
+	// The type of a Secret is immutable and the token is populated by the
+	// token controller of the ServiceAccount.
+	if secret.CreationTimestamp.IsZero() {
+		secret.Type = corev1.SecretTypeServiceAccountToken
+	}
+
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[corev1.ServiceAccountNameKey] = metricsReaderServiceAccountName
+
+	return ctrl.SetControllerReference(m, secret, c.Scheme())
+}
+
+func (r *MonitoringReconciler) deleteMetricsReaderTokenSecret(
+	ctx context.Context,
+	m *addonv1alpha1.Monitoring) error {
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      metricsReaderTokenSecretName,
+			Namespace: m.Namespace,
+		},
+	}
+
+	err := r.Delete(ctx, secret)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete Secret %s in %s: %w", secret.Name, secret.Namespace, err)
+	}
+
+	return nil
+}
+
+// getUserWorkloadEndpoint adapts a scrape endpoint to the user-workload
+// Prometheus, replacing the token file of the Prometheus pod by the metrics
+// reader token Secret.
+func getUserWorkloadEndpoint(endpoint promv1.Endpoint) promv1.Endpoint {
+	if endpoint.BearerTokenFile != "" {
+		endpoint.BearerTokenFile = ""
+		endpoint.BearerTokenSecret = corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: metricsReaderTokenSecretName,
+			},
+			Key: metricsReaderTokenKey,
+		}
+	}
+	return endpoint
+}
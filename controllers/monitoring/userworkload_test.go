@@ -0,0 +1,223 @@
+package monitoring
+
+import (
+	"context"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	promv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+)
+
+var _ = Describe("User-workload monitoring mode", func() {
+	common.ProcessConfig()
+
+	newMonitoring := func(mode addonv1alpha1.MonitoringMode) *addonv1alpha1.Monitoring {
+		return &addonv1alpha1.Monitoring{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "test",
+			},
+			Spec: addonv1alpha1.MonitoringSpec{
+				Mode: mode,
+			},
+		}
+	}
+
+	newClusterPolicy := func(namespace string) *gpuv1.ClusterPolicy {
+		return &gpuv1.ClusterPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: common.GlobalConfig.ClusterPolicyName,
+			},
+			Status: gpuv1.ClusterPolicyStatus{
+				Namespace: namespace,
+			},
+		}
+	}
+
+	reconcileMonitoring := func(r *MonitoringReconciler, m *addonv1alpha1.Monitoring) *addonv1alpha1.Monitoring {
+		_, err := r.Reconcile(context.TODO(), reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: m.Name, Namespace: m.Namespace},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		updated := &addonv1alpha1.Monitoring{}
+		Expect(r.Get(context.TODO(), types.NamespacedName{Name: m.Name, Namespace: m.Namespace}, updated)).To(Succeed())
+		return updated
+	}
+
+	setMode := func(r *MonitoringReconciler, m *addonv1alpha1.Monitoring, mode addonv1alpha1.MonitoringMode) {
+		updated := &addonv1alpha1.Monitoring{}
+		Expect(r.Get(context.TODO(), types.NamespacedName{Name: m.Name, Namespace: m.Namespace}, updated)).To(Succeed())
+		updated.Spec.Mode = mode
+		Expect(r.Update(context.TODO(), updated)).To(Succeed())
+	}
+
+	exists := func(r *MonitoringReconciler, name string, obj client.Object) bool {
+		err := r.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "test"}, obj)
+		if k8serrors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).ShouldNot(HaveOccurred())
+		return true
+	}
+
+	dedicatedConditions := []string{
+		KubeRBACProxyConfigCondition,
+		PrometheusServiceCondition,
+		PrometheusCondition,
+		AlertmanagerCondition,
+		DeadMansSnitchRouteLoadedCondition,
+	}
+
+	Context("when the user-workload monitoring is used", func() {
+		It("should not deploy a private Prometheus stack", func() {
+			m := newMonitoring(addonv1alpha1.MonitoringModeUserWorkload)
+			r := newTestMonitoringReconciler(m, newClusterPolicy("test"))
+
+			updated := reconcileMonitoring(r, m)
+
+			Expect(exists(r, prometheusName, &promv1.Prometheus{})).To(BeFalse())
+			Expect(exists(r, alertManagerName, &promv1.Alertmanager{})).To(BeFalse())
+			Expect(exists(r, prometheusServiceName, &corev1.Service{})).To(BeFalse())
+			Expect(exists(r, prometheusKubeRBACProxyConfigMapName, &corev1.ConfigMap{})).To(BeFalse())
+			Expect(exists(r, alertManagerConfigName, &promv1alpha1.AlertmanagerConfig{})).To(BeTrue())
+
+			for _, conditionType := range dedicatedConditions {
+				Expect(meta.FindStatusCondition(updated.Status.Conditions, conditionType)).To(BeNil(), conditionType)
+			}
+			Expect(common.ContainCondition(updated.Status.Conditions,
+				AvailableCondition, metav1.ConditionTrue)).To(BeTrue())
+		})
+
+		It("should create ServiceMonitors the user-workload Prometheus accepts", func() {
+			m := newMonitoring(addonv1alpha1.MonitoringModeUserWorkload)
+			r := newTestMonitoringReconciler(m, newClusterPolicy("test"))
+
+			discovered, err := r.reconcileServiceMonitors(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(discovered).To(BeTrue())
+
+			for _, name := range []string{
+				controllerManagerServiceMonitorName,
+				dcgmExporterServiceMonitorName,
+				nodeStatusExporterServiceMonitorName,
+			} {
+				sm := &promv1.ServiceMonitor{}
+				Expect(exists(r, name, sm)).To(BeTrue(), name)
+				Expect(sm.Spec.NamespaceSelector.MatchNames).To(BeEmpty(), name)
+				Expect(sm.Spec.Endpoints[0].BearerTokenFile).To(BeEmpty(), name)
+			}
+
+			sm := &promv1.ServiceMonitor{}
+			Expect(exists(r, controllerManagerServiceMonitorName, sm)).To(BeTrue())
+			Expect(sm.Spec.Endpoints[0].BearerTokenSecret.Name).To(Equal(metricsReaderTokenSecretName))
+			Expect(sm.Spec.Endpoints[0].BearerTokenSecret.Key).To(Equal(metricsReaderTokenKey))
+
+			secret := &corev1.Secret{}
+			Expect(exists(r, metricsReaderTokenSecretName, secret)).To(BeTrue())
+			Expect(secret.Type).To(Equal(corev1.SecretTypeServiceAccountToken))
+			Expect(secret.Annotations).To(HaveKeyWithValue(corev1.ServiceAccountNameKey, metricsReaderServiceAccountName))
+		})
+
+		It("should skip the operands installed outside of the addon namespace", func() {
+			m := newMonitoring(addonv1alpha1.MonitoringModeUserWorkload)
+			r := newTestMonitoringReconciler(m, newClusterPolicy("nvidia-gpu-operator"))
+
+			discovered, err := r.reconcileServiceMonitors(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(discovered).To(BeTrue())
+
+			Expect(exists(r, controllerManagerServiceMonitorName, &promv1.ServiceMonitor{})).To(BeTrue())
+			Expect(exists(r, dcgmExporterServiceMonitorName, &promv1.ServiceMonitor{})).To(BeFalse())
+			Expect(exists(r, nodeStatusExporterServiceMonitorName, &promv1.ServiceMonitor{})).To(BeFalse())
+			Expect(exists(r, prometheusServiceMonitorName, &promv1.ServiceMonitor{})).To(BeFalse())
+		})
+
+		It("should evaluate the alerts in the user-workload Prometheus with the cluster identity", func() {
+			m := newMonitoring(addonv1alpha1.MonitoringModeUserWorkload)
+			r := newTestMonitoringReconciler(m)
+
+			Expect(r.reconcilePrometheusRule(context.TODO(), m)).To(Succeed())
+
+			rule := &promv1.PrometheusRule{}
+			Expect(exists(r, prometheusRuleName, rule)).To(BeTrue())
+			Expect(rule.Labels).To(HaveKeyWithValue(ruleEvaluationScopeLabel, ruleEvaluationScopeLeaf))
+
+			for _, group := range rule.Spec.Groups {
+				for _, alert := range group.Rules {
+					if alert.Alert == "" {
+						continue
+					}
+					Expect(alert.Labels).To(HaveKey(addonVersionLabel), alert.Alert)
+				}
+			}
+		})
+
+		It("should not check the remote write", func() {
+			m := newMonitoring(addonv1alpha1.MonitoringModeUserWorkload)
+			m.Spec.RemoteWrite = &addonv1alpha1.MonitoringRemoteWriteSpec{
+				URL: "http://127.0.0.1:1/api/v1/write",
+			}
+			r := newTestMonitoringReconciler(m)
+
+			condition, err := r.getRemoteWriteCondition(context.TODO(), m)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(condition.Reason).To(Equal("NotSupportedInMode"))
+		})
+	})
+
+	Context("when switching between modes", func() {
+		It("should remove and restore the private Prometheus stack", func() {
+			m := newMonitoring(addonv1alpha1.MonitoringModeDedicated)
+			r := newTestMonitoringReconciler(m, newClusterPolicy("test"))
+
+			updated := reconcileMonitoring(r, m)
+			Expect(exists(r, prometheusName, &promv1.Prometheus{})).To(BeTrue())
+			Expect(exists(r, alertManagerName, &promv1.Alertmanager{})).To(BeTrue())
+			Expect(meta.FindStatusCondition(updated.Status.Conditions, PrometheusCondition)).ToNot(BeNil())
+
+			setMode(r, m, addonv1alpha1.MonitoringModeUserWorkload)
+			updated = reconcileMonitoring(r, m)
+
+			Expect(exists(r, prometheusName, &promv1.Prometheus{})).To(BeFalse())
+			Expect(exists(r, alertManagerName, &promv1.Alertmanager{})).To(BeFalse())
+			Expect(exists(r, prometheusServiceName, &corev1.Service{})).To(BeFalse())
+			Expect(exists(r, prometheusKubeRBACProxyConfigMapName, &corev1.ConfigMap{})).To(BeFalse())
+			Expect(exists(r, metricsReaderTokenSecretName, &corev1.Secret{})).To(BeTrue())
+			for _, conditionType := range dedicatedConditions {
+				Expect(meta.FindStatusCondition(updated.Status.Conditions, conditionType)).To(BeNil(), conditionType)
+			}
+
+			setMode(r, m, addonv1alpha1.MonitoringModeDedicated)
+			updated = reconcileMonitoring(r, m)
+
+			Expect(exists(r, prometheusName, &promv1.Prometheus{})).To(BeTrue())
+			Expect(exists(r, alertManagerName, &promv1.Alertmanager{})).To(BeTrue())
+			Expect(exists(r, metricsReaderTokenSecretName, &corev1.Secret{})).To(BeFalse())
+			Expect(meta.FindStatusCondition(updated.Status.Conditions, PrometheusCondition)).ToNot(BeNil())
+
+			rule := &promv1.PrometheusRule{}
+			Expect(exists(r, prometheusRuleName, rule)).To(BeTrue())
+			Expect(rule.Labels).ToNot(HaveKey(ruleEvaluationScopeLabel))
+
+			sm := &promv1.ServiceMonitor{}
+			Expect(exists(r, controllerManagerServiceMonitorName, sm)).To(BeTrue())
+			Expect(sm.Spec.NamespaceSelector.MatchNames).To(Equal([]string{"test"}))
+			Expect(sm.Spec.Endpoints[0].BearerTokenFile).ToNot(BeEmpty())
+			Expect(sm.Spec.Endpoints[0].BearerTokenSecret.Name).To(BeEmpty())
+		})
+	})
+})
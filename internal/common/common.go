@@ -2,6 +2,7 @@ package common
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -41,15 +42,34 @@ type config struct {
 	// RELATED_IMAGE_PLUGIN_IMAGE
 	ConsolePluginImage string `envconfig:"RELATED_IMAGE_CONSOLE_PLUGIN" default:"quay.io/edge-infrastructure/console-plugin-nvidia-gpu@sha256:cec17462944cb2f800e7477101e0470c5f7a07998c012ef7470e14993ebebf40"`
 
+	// RELATED_IMAGE_KUBE_RBAC_PROXY_4_*, the kube-rbac-proxy image shipped
+	// with each OpenShift version, set by the operator Deployment.
+	KubeRBACProxyImage4_9  string `envconfig:"RELATED_IMAGE_KUBE_RBAC_PROXY_4_9"`
+	KubeRBACProxyImage4_10 string `envconfig:"RELATED_IMAGE_KUBE_RBAC_PROXY_4_10"`
+	KubeRBACProxyImage4_11 string `envconfig:"RELATED_IMAGE_KUBE_RBAC_PROXY_4_11"`
+
 	// PAGER_DUTY_SECRET_NAME
 	PagerDutySecretName string `envconfig:"PAGER_DUTY_SECRET_NAME" default:"pagerduty"`
 
+	// UNINSTALL_REMOVE_CRDS, whether the removal of the addon also removes
+	// the CRDs of the GPU and NFD operators, along with all their CRs.
+	UninstallRemoveCRDs bool `envconfig:"UNINSTALL_REMOVE_CRDS" default:"false"`
+
+	// ENABLE_WEBHOOKS, whether the admission webhooks of the GPUAddon and
+	// Monitoring CRDs are served, disabled by `make run` as the serving
+	// certificates only exist in the cluster.
+	EnableWebhooks bool `envconfig:"ENABLE_WEBHOOKS" default:"true"`
+
 	// DEAD_MANS_SNITCH_SECRET_NAME
 	DeadMansSnitchSecretName string `envconfig:"PAGE_RDUTY_SECRET_NAME" default:"deadmanssnitch"`
 }
 
 var GlobalConfig config
 
+// ErrNoCompletedClusterVersion is returned while no OpenShift version has
+// been completely rolled out to the cluster yet.
+var ErrNoCompletedClusterVersion = errors.New("failed to find Completed Cluster Version")
+
 func ProcessConfig() {
 	_ = envconfig.Process("nvidia-gpu-addon", &GlobalConfig)
 }
@@ -103,7 +123,7 @@ func GetOpenShiftVersion(client client.Client) (string, error) {
 		return fmt.Sprintf("%d.%d", ocpVersion.Major(), ocpVersion.Minor()), nil
 	}
 
-	return "", fmt.Errorf("failed to find Completed Cluster Version")
+	return "", ErrNoCompletedClusterVersion
 }
 
 func IsOpenShiftVersionAtLeast(client client.Client, v string) (bool, error) {
@@ -131,5 +151,5 @@ func IsOpenShiftVersionAtLeast(client client.Client, v string) (bool, error) {
 		return ocpVersion.AtLeast(version), nil
 	}
 
-	return false, fmt.Errorf("failed to find Completed Cluster Version")
+	return false, ErrNoCompletedClusterVersion
 }
@@ -0,0 +1,214 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// cacheSyncTimeout bounds the wait for the informer caches in a probe,
+	// well below the probe timeouts.
+	cacheSyncTimeout = time.Second
+)
+
+// CacheSyncer is implemented by the manager cache.
+type CacheSyncer interface {
+	WaitForCacheSync(ctx context.Context) bool
+}
+
+// CacheSyncChecker fails until the informer caches of the manager are synced.
+func CacheSyncChecker(c CacheSyncer) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), cacheSyncTimeout)
+		defer cancel()
+
+		if !c.WaitForCacheSync(ctx) {
+			return errors.New("the informer caches are not synced yet")
+		}
+		return nil
+	}
+}
+
+// Flag is a readiness condition set once, e.g. when an optional watch could
+// be established.
+type Flag struct {
+	set     int32
+	message string
+}
+
+// NewFlag returns an unset Flag, failing its check with the given message.
+func NewFlag(message string) *Flag {
+	return &Flag{message: message}
+}
+
+// Set marks the condition as met.
+func (f *Flag) Set() {
+	atomic.StoreInt32(&f.set, 1)
+}
+
+// Checker fails until the Flag is set.
+func (f *Flag) Checker() healthz.Checker {
+	return func(_ *http.Request) error {
+		if atomic.LoadInt32(&f.set) == 0 {
+			return errors.New(f.message)
+		}
+		return nil
+	}
+}
+
+// WithGracePeriod ignores the failures of a check for the given period from
+// now, e.g. so that it does not fail on a fresh install while the addon is
+// still installing its dependencies.
+func WithGracePeriod(check healthz.Checker, gracePeriod time.Duration) healthz.Checker {
+	return withGracePeriod(check, gracePeriod, time.Now)
+}
+
+func withGracePeriod(check healthz.Checker, gracePeriod time.Duration, now func() time.Time) healthz.Checker {
+	deadline := now().Add(gracePeriod)
+	return func(req *http.Request) error {
+		err := check(req)
+		if err != nil && now().Before(deadline) {
+			return nil
+		}
+		return err
+	}
+}
+
+// ReconcileTracker records the outcome of the reconciliations of the
+// controllers, to tell a controller failing to reconcile from an idle one.
+// The outcome is reported by its readiness checks and in metrics.
+type ReconcileTracker struct {
+	mu          sync.Mutex
+	controllers map[string]*reconcileState
+	now         func() time.Time
+}
+
+type reconcileState struct {
+	since       time.Time
+	lastSuccess time.Time
+	failing     bool
+	lastError   string
+}
+
+var (
+	ControllerReconcileFailing = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "nvidia_gpuaddon_controller_reconcile_failing",
+			Help: "Reports whether the last reconciliation of a controller of the NVIDIA GPUAddon operator failed",
+		},
+		[]string{"controller"},
+	)
+
+	ControllerReconcileLastSuccess = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "nvidia_gpuaddon_controller_reconcile_last_success_timestamp_seconds",
+			Help: "Time of the last successful reconciliation of a controller of the NVIDIA GPUAddon operator",
+		},
+		[]string{"controller"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		ControllerReconcileFailing,
+		ControllerReconcileLastSuccess,
+	)
+}
+
+// NewReconcileTracker returns an empty ReconcileTracker.
+func NewReconcileTracker() *ReconcileTracker {
+	return &ReconcileTracker{
+		controllers: map[string]*reconcileState{},
+		now:         time.Now,
+	}
+}
+
+// Track wraps the reconciler of a controller to record the outcome of its
+// reconciliations. The reconciler is returned as is on a nil tracker.
+func (t *ReconcileTracker) Track(controller string, r reconcile.Reconciler) reconcile.Reconciler {
+	if t == nil {
+		return r
+	}
+
+	// The controller is reported from its start, even before reconciling.
+	t.mu.Lock()
+	t.state(controller)
+	t.mu.Unlock()
+	ControllerReconcileFailing.WithLabelValues(controller).Set(0)
+
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		result, err := r.Reconcile(ctx, req)
+		t.Observe(controller, err)
+		return result, err
+	})
+}
+
+// Observe records the outcome of a reconciliation of a controller.
+func (t *ReconcileTracker) Observe(controller string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state := t.state(controller)
+	if err != nil {
+		state.failing = true
+		state.lastError = err.Error()
+		ControllerReconcileFailing.WithLabelValues(controller).Set(1)
+		return
+	}
+
+	state.lastSuccess = t.now()
+	state.failing = false
+	state.lastError = ""
+	ControllerReconcileFailing.WithLabelValues(controller).Set(0)
+	ControllerReconcileLastSuccess.WithLabelValues(controller).Set(float64(state.lastSuccess.Unix()))
+}
+
+// Checker fails once the reconciliations of a controller have kept failing
+// for longer than maxFailureAge since its last successful reconciliation, or
+// since it started if none succeeded yet. An idle controller stays ready.
+func (t *ReconcileTracker) Checker(controller string, maxFailureAge time.Duration) healthz.Checker {
+	return func(_ *http.Request) error {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		state := t.state(controller)
+		if !state.failing {
+			return nil
+		}
+
+		if state.lastSuccess.IsZero() {
+			if age := t.now().Sub(state.since); age > maxFailureAge {
+				return fmt.Errorf("the %s controller has not reconciled successfully since it started %s ago: %s",
+					controller, age.Round(time.Second), state.lastError)
+			}
+			return nil
+		}
+
+		if age := t.now().Sub(state.lastSuccess); age > maxFailureAge {
+			return fmt.Errorf("the %s controller last reconciled successfully %s ago: %s",
+				controller, age.Round(time.Second), state.lastError)
+		}
+
+		return nil
+	}
+}
+
+// state returns the state of a controller, t.mu being held.
+func (t *ReconcileTracker) state(controller string) *reconcileState {
+	state, ok := t.controllers[controller]
+	if !ok {
+		state = &reconcileState{since: t.now()}
+		t.controllers[controller] = state
+	}
+	return state
+}
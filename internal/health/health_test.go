@@ -0,0 +1,140 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type testCache struct {
+	synced bool
+}
+
+func (c *testCache) WaitForCacheSync(ctx context.Context) bool {
+	if !c.synced {
+		<-ctx.Done()
+	}
+	return c.synced
+}
+
+var _ = Describe("Readiness checks", func() {
+	req := httptest.NewRequest("GET", "/readyz", nil)
+
+	It("should wait for the informer caches", func() {
+		c := &testCache{}
+		check := CacheSyncChecker(c)
+		Expect(check(req)).ToNot(Succeed())
+
+		c.synced = true
+		Expect(check(req)).To(Succeed())
+	})
+
+	It("should wait for a flag to be set", func() {
+		flag := NewFlag("not there yet")
+		check := flag.Checker()
+		Expect(check(req)).To(MatchError("not there yet"))
+
+		flag.Set()
+		Expect(check(req)).To(Succeed())
+	})
+
+	It("should ignore the failures during the grace period", func() {
+		now := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+		check := withGracePeriod(NewFlag("not there yet").Checker(), time.Hour, func() time.Time { return now })
+		Expect(check(req)).To(Succeed())
+
+		now = now.Add(2 * time.Hour)
+		Expect(check(req)).To(MatchError("not there yet"))
+	})
+})
+
+var _ = Describe("Reconcile tracker", func() {
+	var (
+		t   *ReconcileTracker
+		now time.Time
+	)
+
+	getGauge := func(g *prometheus.GaugeVec, labels ...string) float64 {
+		metric := &dto.Metric{}
+		Expect(g.WithLabelValues(labels...).Write(metric)).To(Succeed())
+		return metric.GetGauge().GetValue()
+	}
+
+	BeforeEach(func() {
+		now = time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+		t = NewReconcileTracker()
+		t.now = func() time.Time { return now }
+	})
+
+	track := func(err error) reconcile.Reconciler {
+		return t.Track("test", reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			return reconcile.Result{}, err
+		}))
+	}
+
+	It("should report an idle controller as not failing", func() {
+		track(nil)
+
+		Expect(getGauge(ControllerReconcileFailing, "test")).To(BeZero())
+	})
+
+	It("should report a failing controller until it succeeds", func() {
+		_, err := track(nil).Reconcile(context.TODO(), reconcile.Request{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getGauge(ControllerReconcileLastSuccess, "test")).To(Equal(float64(now.Unix())))
+
+		previous := now
+		now = now.Add(time.Hour)
+		_, err = track(errors.New("boom")).Reconcile(context.TODO(), reconcile.Request{})
+		Expect(err).To(HaveOccurred())
+		Expect(getGauge(ControllerReconcileFailing, "test")).To(Equal(1.0))
+		Expect(getGauge(ControllerReconcileLastSuccess, "test")).To(Equal(float64(previous.Unix())))
+
+		t.Observe("test", nil)
+		Expect(getGauge(ControllerReconcileFailing, "test")).To(BeZero())
+		Expect(getGauge(ControllerReconcileLastSuccess, "test")).To(Equal(float64(now.Unix())))
+	})
+
+	Context("readiness", func() {
+		req := httptest.NewRequest("GET", "/readyz", nil)
+
+		It("should keep an idle controller ready", func() {
+			track(nil)
+			now = now.Add(24 * time.Hour)
+
+			Expect(t.Checker("test", time.Hour)(req)).To(Succeed())
+		})
+
+		It("should report a controller failing since it started", func() {
+			r := track(errors.New("boom"))
+			_, err := r.Reconcile(context.TODO(), reconcile.Request{})
+			Expect(err).To(HaveOccurred())
+
+			Expect(t.Checker("test", time.Hour)(req)).To(Succeed())
+
+			now = now.Add(2 * time.Hour)
+			Expect(t.Checker("test", time.Hour)(req)).To(MatchError(ContainSubstring("since it started 2h0m0s ago: boom")))
+		})
+
+		It("should report a controller failing since its last success", func() {
+			t.Observe("test", nil)
+
+			now = now.Add(2 * time.Hour)
+			Expect(t.Checker("test", time.Hour)(req)).To(Succeed())
+
+			t.Observe("test", errors.New("boom"))
+			Expect(t.Checker("test", time.Hour)(req)).To(MatchError(ContainSubstring("last reconciled successfully 2h0m0s ago")))
+
+			t.Observe("test", nil)
+			Expect(t.Checker("test", time.Hour)(req)).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,147 @@
+package prometheus
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+const (
+	defaultQueryTimeout = 30 * time.Second
+
+	// ServiceCAFile is the CA of the serving certificates generated by the
+	// OpenShift service CA, mounted in every pod.
+	ServiceCAFile = "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt"
+
+	// ServiceAccountTokenFile is the token of the pod ServiceAccount.
+	ServiceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// Client runs instant queries against the HTTP API of a Prometheus.
+type Client struct {
+	// Address is the base URL of the Prometheus HTTP API.
+	Address string
+	// Namespace restricts the queries to a namespace, as required by the
+	// tenancy port of the OpenShift Thanos Querier. Not set by default.
+	Namespace string
+	// BearerTokenFile is read on every query, the token being rotated.
+	BearerTokenFile string
+
+	HTTPClient *http.Client
+}
+
+// queryResponse is the envelope of the Prometheus HTTP API responses.
+type queryResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// NewInClusterClient returns a Client authenticated with the token of the pod
+// ServiceAccount and trusting the OpenShift service CA.
+func NewInClusterClient(address string, namespace string) (*Client, error) {
+	ca, err := os.ReadFile(ServiceCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the service CA: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("no PEM encoded certificate in %s", ServiceCAFile)
+	}
+
+	return &Client{
+		Address:         address,
+		Namespace:       namespace,
+		BearerTokenFile: ServiceAccountTokenFile,
+		HTTPClient: &http.Client{
+			Timeout: defaultQueryTimeout,
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{
+					MinVersion: tls.VersionTLS12,
+					RootCAs:    pool,
+				},
+			},
+		},
+	}, nil
+}
+
+// Query evaluates an instant query returning a vector at the given time.
+func (c *Client) Query(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	if !ts.IsZero() {
+		params.Set("time", fmt.Sprintf("%d", ts.Unix()))
+	}
+	if c.Namespace != "" {
+		params.Set("namespace", c.Namespace)
+	}
+
+	// The kube-rbac-proxy of the addon Prometheus authorizes the query
+	// endpoints through a SubjectAccessReview of the get verb on the
+	// /api/v1/query non-resource URL, granted to the operator by its RBAC.
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		strings.TrimSuffix(c.Address, "/")+"/api/v1/query?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Prometheus query request: %w", err)
+	}
+
+	if c.BearerTokenFile != "" {
+		token, err := os.ReadFile(c.BearerTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the bearer token: %w", err)
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", strings.TrimSpace(string(token))))
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Prometheus %s unreachable: %w", c.Address, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the Prometheus response: %w", err)
+	}
+
+	response := &queryResponse{}
+	if err := json.Unmarshal(body, response); err != nil {
+		return nil, fmt.Errorf("Prometheus %s responded %s: %w", c.Address, resp.Status, err)
+	}
+
+	if response.Status != "success" {
+		return nil, fmt.Errorf("query %q failed: %s: %s", query, response.ErrorType, response.Error)
+	}
+
+	if response.Data.ResultType != model.ValVector.String() {
+		return nil, fmt.Errorf("query %q returned a %s instead of a vector", query, response.Data.ResultType)
+	}
+
+	vector := model.Vector{}
+	if err := json.Unmarshal(response.Data.Result, &vector); err != nil {
+		return nil, fmt.Errorf("unable to parse the result of query %q: %w", query, err)
+	}
+
+	return vector, nil
+}
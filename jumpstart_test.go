@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	addonv1alpha1 "github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/api/v1alpha1"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/health"
+)
+
+// getFreeAddress returns a local address nothing listens on.
+func getFreeAddress() string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+	defer l.Close()
+
+	return l.Addr().String()
+}
+
+var _ = Describe("Jumpstart through the admission webhooks", func() {
+	var (
+		testEnv *envtest.Environment
+		c       client.Client
+		cancel  context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+			Skip("KUBEBUILDER_ASSETS is not set")
+		}
+		common.ProcessConfig()
+
+		// Only the addon CRDs are installed, as on a fresh install where
+		// the GPU operator, and so the ClusterPolicy CRD, is not there yet.
+		testEnv = &envtest.Environment{
+			CRDDirectoryPaths:     []string{filepath.Join("config", "crd", "bases")},
+			ErrorIfCRDPathMissing: true,
+			WebhookInstallOptions: envtest.WebhookInstallOptions{
+				Paths: []string{filepath.Join("config", "webhook")},
+			},
+		}
+		cfg, err := testEnv.Start()
+		Expect(err).ToNot(HaveOccurred())
+
+		c, err = client.New(cfg, client.Options{Scheme: scheme})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.Create(context.TODO(), &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: common.GlobalConfig.AddonNamespace},
+		})).To(Succeed())
+
+		probeAddr := getFreeAddress()
+		webhookInstallOptions := &testEnv.WebhookInstallOptions
+		mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+			Scheme:                 scheme,
+			Namespace:              common.GlobalConfig.AddonNamespace,
+			Host:                   webhookInstallOptions.LocalServingHost,
+			Port:                   webhookInstallOptions.LocalServingPort,
+			CertDir:                webhookInstallOptions.LocalServingCertDir,
+			HealthProbeBindAddress: probeAddr,
+			MetricsBindAddress:     "0",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect((&addonv1alpha1.GPUAddon{}).SetupWebhookWithManager(mgr)).To(Succeed())
+		Expect((&addonv1alpha1.Monitoring{}).SetupWebhookWithManager(mgr)).To(Succeed())
+		Expect(addReadyzChecks(mgr,
+			health.NewReconcileTracker(),
+			health.NewFlag("the ClusterPolicy CRD is not available yet"))).To(Succeed())
+		Expect(mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			return jumpstart(ctx, c)
+		}))).To(Succeed())
+
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		go func() {
+			defer GinkgoRecover()
+			Expect(mgr.Start(ctx)).To(Succeed())
+		}()
+
+		DeferCleanup(func() {
+			cancel()
+			Expect(testEnv.Stop()).To(Succeed())
+		})
+
+		// The readiness gates the routing to the webhooks in a cluster.
+		Eventually(func() (int, error) {
+			resp, err := http.Get(fmt.Sprintf("http://%s/readyz", probeAddr))
+			if err != nil {
+				return 0, err
+			}
+			defer resp.Body.Close()
+			return resp.StatusCode, nil
+		}, 30*time.Second).Should(Equal(http.StatusOK))
+
+		addr := fmt.Sprintf("%s:%d", webhookInstallOptions.LocalServingHost, webhookInstallOptions.LocalServingPort)
+		Eventually(func() error {
+			conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", addr,
+				&tls.Config{InsecureSkipVerify: true}) // #nosec G402
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		}).Should(Succeed())
+	})
+
+	It("should create the CRs while the ClusterPolicy CRD is absent", func() {
+		err := c.List(context.TODO(), &gpuv1.ClusterPolicyList{})
+		Expect(meta.IsNoMatchError(err)).To(BeTrue())
+
+		key := types.NamespacedName{
+			Name:      common.GlobalConfig.AddonID,
+			Namespace: common.GlobalConfig.AddonNamespace,
+		}
+		Eventually(func() error {
+			return c.Get(context.TODO(), key, &addonv1alpha1.GPUAddon{})
+		}, 30*time.Second).Should(Succeed())
+		Eventually(func() error {
+			return c.Get(context.TODO(), key, &addonv1alpha1.Monitoring{})
+		}, 30*time.Second).Should(Succeed())
+
+		// Defaulted by the webhook.
+		gpuAddon := &addonv1alpha1.GPUAddon{}
+		Expect(c.Get(context.TODO(), key, gpuAddon)).To(Succeed())
+		Expect(gpuAddon.Spec.FinalizerTimeout).ToNot(BeNil())
+	})
+})
@@ -18,6 +18,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -30,6 +31,8 @@ import (
 
 	configv1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -46,6 +49,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	gpuv1 "github.com/NVIDIA/gpu-operator/api/v1"
@@ -61,10 +65,26 @@ import (
 	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/controllers/gpuaddon"
 	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/controllers/monitoring"
 	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/common"
+	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/health"
 	"github.com/rh-ecosystem-edge/nvidia-gpu-addon-operator/internal/version"
 	//+kubebuilder:scaffold:imports
 )
 
+const (
+	// reconcileFailureTolerance is how long a controller may keep failing to
+	// reconcile before the operator reports not ready.
+	reconcileFailureTolerance = 30 * time.Minute
+
+	// clusterPolicyCRDGracePeriod is how long the ClusterPolicy CRD may be
+	// missing from the start of the operator, e.g. while the GPU operator is
+	// being installed, before the operator reports not ready.
+	clusterPolicyCRDGracePeriod = 30 * time.Minute
+
+	// jumpstartRetryInterval is how often the creation of the addon CRs is
+	// retried, e.g. while the admission webhooks are not reachable yet.
+	jumpstartRetryInterval = 5 * time.Second
+)
+
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
@@ -83,6 +103,7 @@ func init() {
 	utilruntime.Must(operatorv1.AddToScheme(scheme))
 	utilruntime.Must(promv1.AddToScheme(scheme))
 	utilruntime.Must(promv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -113,15 +134,27 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "f75da35c.addons.rh-ecosystem-edge.io",
+		// The pods holding idle GPUs run outside of the addon namespace the
+		// cache is restricted to. The CRDs and namespaces are only read once
+		// for the uninstall report.
+		ClientDisableCacheFor: []client.Object{
+			&corev1.Pod{},
+			&corev1.Namespace{},
+			&apiextensionsv1.CustomResourceDefinition{},
+		},
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	reconcileTracker := health.NewReconcileTracker()
+	clusterPolicyWatched := health.NewFlag("the ClusterPolicy CRD is not available yet")
+
 	gpuAddonController, err := (&gpuaddon.GPUAddonReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:           mgr.GetClient(),
+		Scheme:           mgr.GetScheme(),
+		ReconcileTracker: reconcileTracker,
 	}).SetupWithManager(mgr)
 	if err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "GPUAddon")
@@ -133,29 +166,46 @@ func main() {
 			setupLog.Error(err, "unable to wait and watch for ClusterPolicy CRD")
 			return
 		}
+		gpuaddon.ClusterPolicyCRDAvailable.Set(1)
+		clusterPolicyWatched.Set()
 	}()
 
 	if err = (&configmap.ConfigMapReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:           mgr.GetClient(),
+		Scheme:           mgr.GetScheme(),
+		ReconcileTracker: reconcileTracker,
+		Recorder:         mgr.GetEventRecorderFor("nvidia-gpu-addon-uninstall"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ConfigMap")
 		os.Exit(1)
 	}
 	if err = (&monitoring.MonitoringReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:           mgr.GetClient(),
+		Scheme:           mgr.GetScheme(),
+		Prometheus:       &monitoring.InClusterPrometheusQuerier{},
+		Recorder:         mgr.GetEventRecorderFor("nvidia-gpu-addon-monitoring"),
+		ReconcileTracker: reconcileTracker,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Monitoring")
 		os.Exit(1)
 	}
+	if common.GlobalConfig.EnableWebhooks {
+		if err = (&nvidiav1alpha1.GPUAddon{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "GPUAddon")
+			os.Exit(1)
+		}
+		if err = (&nvidiav1alpha1.Monitoring{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Monitoring")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+	if err := addReadyzChecks(mgr, reconcileTracker, clusterPolicyWatched); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
@@ -165,12 +215,12 @@ func main() {
 		setupLog.Error(err, "failed to create client to jumpstart addon")
 		os.Exit(1)
 	}
-	if err := jumpstartAddon(c); err != nil {
-		setupLog.Error(err, "failed to jumpstart addon")
-		os.Exit(1)
-	}
-	if err := jumpstartMonitoring(c); err != nil {
-		setupLog.Error(err, "failed to jumpstart monitoring")
+	// The CRs are created through the admission webhooks served by the
+	// manager, so only once it runs.
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		return jumpstart(ctx, c)
+	})); err != nil {
+		setupLog.Error(err, "unable to set up the addon jumpstart")
 		os.Exit(1)
 	}
 
@@ -181,6 +231,30 @@ func main() {
 	}
 }
 
+// addReadyzChecks sets up the readiness checks, listed by /readyz?verbose
+// and each one served individually under /readyz/<name>. The ClusterPolicy
+// CRD and the reconcile checks only fail past their grace period, so that a
+// fresh install, where the GPU operator is installed from the GPUAddon CR
+// admitted by the webhooks, becomes ready. The webhook and metrics Services
+// publish the pods not ready, so that the webhooks and the alerts on the
+// metrics of a failing operator keep working.
+func addReadyzChecks(mgr manager.Manager, reconcileTracker *health.ReconcileTracker, clusterPolicyWatched *health.Flag) error {
+	readyzChecks := map[string]healthz.Checker{
+		"informer-sync":        health.CacheSyncChecker(mgr.GetCache()),
+		"clusterpolicy-crd":    health.WithGracePeriod(clusterPolicyWatched.Checker(), clusterPolicyCRDGracePeriod),
+		"gpuaddon-reconcile":   reconcileTracker.Checker("gpuaddon", reconcileFailureTolerance),
+		"configmap-reconcile":  reconcileTracker.Checker("configmap", reconcileFailureTolerance),
+		"monitoring-reconcile": reconcileTracker.Checker("monitoring", reconcileFailureTolerance),
+	}
+	for name, check := range readyzChecks {
+		if err := mgr.AddReadyzCheck(name, check); err != nil {
+			return fmt.Errorf("unable to set up ready check %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
 func watchForOwnClusterPoliciesWhenAvailable(c controller.Controller) error {
 	if err := wait.PollInfinite(time.Second, isClusterPolicyAvailable()); err != nil {
 		return fmt.Errorf("unable to wait for ClusterPolicy CRD: %w", err)
@@ -216,6 +290,40 @@ func isClusterPolicyAvailable() wait.ConditionFunc {
 	}
 }
 
+// jumpstart creates the GPUAddon and Monitoring CRs, unless the removal of the
+// addon is in progress, retrying until it succeeds or the manager stops.
+func jumpstart(ctx context.Context, c client.Client) error {
+	err := wait.PollImmediateUntil(jumpstartRetryInterval, func() (bool, error) {
+		// The CRs removed by an uninstall in progress must not be created again.
+		uninstalling, err := configmap.IsUninstallRequested(ctx, c)
+		if err != nil {
+			setupLog.Error(err, "failed to check for an uninstall request")
+			return false, nil
+		}
+		if uninstalling {
+			setupLog.Info("addon uninstall requested, not jumpstarting the addon")
+			return true, nil
+		}
+
+		if err := jumpstartAddon(c); err != nil {
+			setupLog.Error(err, "failed to jumpstart addon")
+			return false, nil
+		}
+		if err := jumpstartMonitoring(c); err != nil {
+			setupLog.Error(err, "failed to jumpstart monitoring")
+			return false, nil
+		}
+
+		return true, nil
+	}, ctx.Done())
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		// The manager stopped.
+		return nil
+	}
+
+	return err
+}
+
 func jumpstartAddon(client client.Client) error {
 	gpuAddon := &nvidiav1alpha1.GPUAddon{}
 	err := client.Get(context.TODO(), types.NamespacedName{